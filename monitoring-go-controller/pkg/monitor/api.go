@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultEventPageSize = 100
+	maxEventPageSize     = 1000
+)
+
+// eventPage is the response envelope of GET /events. NextOffset is the
+// offset of the following page, or null on the last page.
+type eventPage struct {
+	Items      []PodEvent `json:"items"`
+	NextOffset *int       `json:"next_offset"`
+}
+
+// handleEvents serves stored events filtered by the namespace, pod, type and
+// since query parameters, paginated with limit and offset. since accepts an
+// RFC 3339 timestamp or a duration such as 1h, meaning that long ago.
+func (pm *PodMonitor) handleEvents(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rw.Header().Set("Allow", http.MethodGet)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !pm.authorized(r) {
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+	query := EventQuery{
+		Namespace: params.Get("namespace"),
+		PodName:   params.Get("pod"),
+		EventType: params.Get("type"),
+		Limit:     defaultEventPageSize,
+	}
+
+	var err error
+	if value := params.Get("since"); value != "" {
+		if query.Since, err = parseSince(value, time.Now()); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if value := params.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil || query.Limit < 1 || query.Limit > maxEventPageSize {
+			http.Error(rw, fmt.Sprintf("limit must be between 1 and %d", maxEventPageSize), http.StatusBadRequest)
+			return
+		}
+	}
+	if value := params.Get("offset"); value != "" {
+		if query.Offset, err = strconv.Atoi(value); err != nil || query.Offset < 0 {
+			http.Error(rw, "offset must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Fetch one extra event to know whether there is a next page.
+	pageSize := query.Limit
+	query.Limit++
+	events, err := pm.store.Query(r.Context(), query)
+	if err != nil {
+		pm.logger.Printf("❌ Failed to query events: %v", err)
+		http.Error(rw, "failed to query events", http.StatusInternalServerError)
+		return
+	}
+
+	page := eventPage{Items: events}
+	if len(events) > pageSize {
+		page.Items = events[:pageSize]
+		next := query.Offset + pageSize
+		page.NextOffset = &next
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(page)
+}
+
+// authorized checks the bearer token when --api-token is set.
+func (pm *PodMonitor) authorized(r *http.Request) bool {
+	if pm.apiToken == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(pm.apiToken)) == 1
+}
+
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+	if ago, err := time.ParseDuration(value); err == nil && ago >= 0 {
+		return now.Add(-ago), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since %q: expected an RFC 3339 timestamp or a duration such as 1h", value)
+}
@@ -0,0 +1,171 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestAPI serves a monitor's GET /events over a store holding events.
+func newTestAPI(t *testing.T, token string, events ...PodEvent) *httptest.Server {
+	t.Helper()
+	pm, _ := newTestMonitor(t, "default")
+	store, err := openEventStore(filepath.Join(t.TempDir(), "events.db"), pm.logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.db.Close() })
+	if err := store.write(events); err != nil {
+		t.Fatal(err)
+	}
+	pm.store = store
+	pm.apiToken = token
+
+	server := httptest.NewServer(http.HandlerFunc(pm.handleEvents))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandleEvents(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	server := newTestAPI(t, "",
+		PodEvent{Timestamp: at, EventType: "ADDED", PodName: "web", Namespace: "shop"},
+		PodEvent{Timestamp: at.Add(time.Minute), EventType: "MODIFIED", PodName: "web", Namespace: "shop"},
+		PodEvent{Timestamp: at.Add(2 * time.Minute), EventType: "ADDED", PodName: "api", Namespace: "shop"},
+		PodEvent{Timestamp: at.Add(3 * time.Minute), EventType: "DELETED", PodName: "web", Namespace: "other"},
+	)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+		next  *int
+	}{
+		{name: "all newest first", query: "", want: []string{"DELETED other/web", "ADDED shop/api", "MODIFIED shop/web", "ADDED shop/web"}},
+		{name: "namespace", query: "namespace=shop", want: []string{"ADDED shop/api", "MODIFIED shop/web", "ADDED shop/web"}},
+		{name: "pod", query: "namespace=shop&pod=web", want: []string{"MODIFIED shop/web", "ADDED shop/web"}},
+		{name: "type", query: "type=ADDED", want: []string{"ADDED shop/api", "ADDED shop/web"}},
+		{name: "since", query: "since=2024-05-01T12:02:00Z", want: []string{"DELETED other/web", "ADDED shop/api"}},
+		{name: "first page", query: "limit=3", want: []string{"DELETED other/web", "ADDED shop/api", "MODIFIED shop/web"}, next: intPtr(3)},
+		{name: "middle page", query: "limit=2&offset=1", want: []string{"ADDED shop/api", "MODIFIED shop/web"}, next: intPtr(3)},
+		{name: "last page", query: "limit=2&offset=2", want: []string{"MODIFIED shop/web", "ADDED shop/web"}},
+		{name: "past the end", query: "offset=10", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/events?" + tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %s, want 200 OK", resp.Status)
+			}
+			if got := resp.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+
+			var page struct {
+				Items      []PodEvent `json:"items"`
+				NextOffset *int       `json:"next_offset"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+				t.Fatal(err)
+			}
+			if page.Items == nil {
+				t.Fatal("items is missing or null, want an array")
+			}
+			got := []string{}
+			for _, event := range page.Items {
+				got = append(got, event.EventType+" "+event.Namespace+"/"+event.PodName)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+			switch {
+			case tt.next == nil && page.NextOffset != nil:
+				t.Errorf("next_offset = %d, want null", *page.NextOffset)
+			case tt.next != nil && (page.NextOffset == nil || *page.NextOffset != *tt.next):
+				t.Errorf("next_offset = %v, want %d", page.NextOffset, *tt.next)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int { return &i }
+
+func TestHandleEventsRejectsBadRequests(t *testing.T) {
+	server := newTestAPI(t, "s3cret")
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		token  string
+		want   int
+	}{
+		{name: "no token", query: "", want: http.StatusUnauthorized},
+		{name: "wrong token", query: "", token: "guess", want: http.StatusUnauthorized},
+		{name: "valid token", query: "", token: "s3cret", want: http.StatusOK},
+		{name: "POST", method: http.MethodPost, token: "s3cret", want: http.StatusMethodNotAllowed},
+		{name: "zero limit", query: "limit=0", token: "s3cret", want: http.StatusBadRequest},
+		{name: "limit over the maximum", query: "limit=1001", token: "s3cret", want: http.StatusBadRequest},
+		{name: "negative offset", query: "offset=-1", token: "s3cret", want: http.StatusBadRequest},
+		{name: "bad since", query: "since=yesterday", token: "s3cret", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, err := http.NewRequest(method, server.URL+"/events?"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("WWW-Authenticate = %q, want Bearer", resp.Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2024-05-01T10:00:00Z", want: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{value: "1h", want: now.Add(-time.Hour)},
+		{value: "0s", want: now},
+		{value: "-1h", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSince(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
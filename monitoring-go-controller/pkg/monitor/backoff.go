@@ -0,0 +1,47 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Watch failure policies: watchFailureExit gives up after maxRetries
+// consecutive failures, watchFailureRetryForever never does.
+const (
+	watchFailureExit         = "exit"
+	watchFailureRetryForever = "retry-forever"
+)
+
+// reconnectBackoff computes the wait before a watch reconnect: exponential
+// growth from initial by factor, capped at max, with full jitter so that many
+// monitors do not reconnect in lockstep after a control-plane blip.
+type reconnectBackoff struct {
+	initial time.Duration
+	factor  float64
+	max     time.Duration
+}
+
+func (b reconnectBackoff) validate() error {
+	if b.initial <= 0 {
+		return fmt.Errorf("backoff initial duration must be positive, got %v", b.initial)
+	}
+	if b.factor < 1 {
+		return fmt.Errorf("backoff factor must be at least 1, got %v", b.factor)
+	}
+	if b.max < b.initial {
+		return fmt.Errorf("backoff max %v must not be below the initial duration %v", b.max, b.initial)
+	}
+	return nil
+}
+
+// delay returns a random wait in [0, min(max, initial*factor^(attempt-1))]
+// for the given 1-based attempt.
+func (b reconnectBackoff) delay(attempt int) time.Duration {
+	ceiling := float64(b.initial) * math.Pow(b.factor, float64(attempt-1))
+	if ceiling > float64(b.max) {
+		ceiling = float64(b.max)
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
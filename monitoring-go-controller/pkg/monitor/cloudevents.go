@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// Output formats: outputFormatJSON writes a PodEvent as is,
+// outputFormatCloudEvents wraps it in a CloudEvents 1.0 envelope.
+const (
+	outputFormatJSON        = "json"
+	outputFormatCloudEvents = "cloudevents"
+)
+
+// cloudEventsContentType is the media type of a CloudEvent in structured
+// JSON mode.
+const cloudEventsContentType = "application/cloudevents+json"
+
+// cloudEventContext holds the CloudEvents attributes logEvent assigns to an
+// event on its way to the sinks.
+type cloudEventContext struct {
+	id        string
+	source    string
+	eventType string
+	subject   string
+}
+
+// newCloudEventContext builds the envelope attributes for event: a type such
+// as k8s.pod.modified, a source naming the cluster and namespace, and a new
+// id so consumers can deduplicate redeliveries.
+func newCloudEventContext(event PodEvent, clusterName string) *cloudEventContext {
+	source := ""
+	if clusterName != "" {
+		source += "/clusters/" + clusterName
+	}
+	if event.Namespace != "" {
+		source += "/namespaces/" + event.Namespace
+	}
+	if source == "" {
+		source = "/"
+	}
+	return &cloudEventContext{
+		id:        string(uuid.NewUUID()),
+		source:    source,
+		eventType: "k8s.pod." + strings.ToLower(event.EventType),
+		subject:   event.PodName,
+	}
+}
+
+// cloudEvent is the structured-mode JSON form of a CloudEvent.
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            PodEvent  `json:"data"`
+}
+
+// marshalCloudEvent writes e wrapped in its envelope. The time attribute is
+// always RFC 3339 as the spec requires; data keeps the configured format.
+func (e PodEvent) marshalCloudEvent() ([]byte, error) {
+	ce := e.cloudEvent
+	e.cloudEvent = nil
+	return json.Marshal(cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              ce.id,
+		Source:          ce.source,
+		Type:            ce.eventType,
+		Subject:         ce.subject,
+		Time:            e.Timestamp,
+		DataContentType: "application/json",
+		Data:            e,
+	})
+}
+
+// contentType is the media type of the event's JSON encoding.
+func (e PodEvent) contentType() string {
+	if e.cloudEvent != nil {
+		return cloudEventsContentType
+	}
+	return "application/json"
+}
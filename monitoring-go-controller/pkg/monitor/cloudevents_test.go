@@ -0,0 +1,133 @@
+package monitor
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink keeps every event the monitor emits to it.
+type recordingSink struct {
+	mu     sync.Mutex
+	events []PodEvent
+}
+
+func (s *recordingSink) Emit(event PodEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestCloudEventEnvelope(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeFormat string
+	}{
+		{name: "rfc3339", timeFormat: "rfc3339"},
+		{name: "unix data timestamps", timeFormat: "unix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OUTPUT_FORMAT", "cloudevents")
+			t.Setenv("CLUSTER_NAME", "prod")
+			t.Setenv("TIME_FORMAT", tt.timeFormat)
+			t.Setenv("TIMEZONE", "UTC")
+			pm, _ := newTestMonitor(t, "shop")
+			sink := &recordingSink{}
+			pm.AddSink(sink)
+
+			at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+			for i := 0; i < 2; i++ {
+				pm.logEvent(PodEvent{Timestamp: at, EventType: "MODIFIED", PodName: "web", Namespace: "shop", Phase: "Running", Message: "Pod updated"})
+			}
+			if len(sink.events) != 2 {
+				t.Fatalf("sink received %d events, want 2", len(sink.events))
+			}
+
+			ids := make(map[string]bool)
+			for _, event := range sink.events {
+				if got := event.contentType(); got != cloudEventsContentType {
+					t.Errorf("content type = %q, want %q", got, cloudEventsContentType)
+				}
+				body, err := json.Marshal(event)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var envelope map[string]json.RawMessage
+				if err := json.Unmarshal(body, &envelope); err != nil {
+					t.Fatal(err)
+				}
+
+				attribute := func(name string) string {
+					t.Helper()
+					var value string
+					if err := json.Unmarshal(envelope[name], &value); err != nil {
+						t.Errorf("attribute %s = %s, want a string", name, envelope[name])
+					}
+					return value
+				}
+				want := map[string]string{
+					"specversion":     "1.0",
+					"source":          "/clusters/prod/namespaces/shop",
+					"type":            "k8s.pod.modified",
+					"subject":         "web",
+					"time":            "2024-05-01T12:30:00Z",
+					"datacontenttype": "application/json",
+				}
+				for name, value := range want {
+					if got := attribute(name); got != value {
+						t.Errorf("%s = %q, want %q", name, got, value)
+					}
+				}
+				id := attribute("id")
+				if id == "" || ids[id] {
+					t.Errorf("id = %q, want a new non-empty id per event", id)
+				}
+				ids[id] = true
+
+				// data is the event exactly as the json output format writes it.
+				plain := event
+				plain.cloudEvent = nil
+				wantData, err := json.Marshal(plain)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var data, expected any
+				if err := json.Unmarshal(envelope["data"], &data); err != nil {
+					t.Fatal(err)
+				}
+				if err := json.Unmarshal(wantData, &expected); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(data, expected) {
+					t.Errorf("data = %s, want %s", envelope["data"], wantData)
+				}
+			}
+		})
+	}
+}
+
+func TestCloudEventSource(t *testing.T) {
+	tests := []struct {
+		cluster   string
+		namespace string
+		want      string
+	}{
+		{cluster: "prod", namespace: "shop", want: "/clusters/prod/namespaces/shop"},
+		{namespace: "shop", want: "/namespaces/shop"},
+		{cluster: "prod", want: "/clusters/prod"},
+		{want: "/"},
+	}
+	for _, tt := range tests {
+		ce := newCloudEventContext(PodEvent{EventType: "ADDED", Namespace: tt.namespace}, tt.cluster)
+		if ce.source != tt.want {
+			t.Errorf("source for cluster %q namespace %q = %q, want %q", tt.cluster, tt.namespace, ce.source, tt.want)
+		}
+		if ce.eventType != "k8s.pod.added" {
+			t.Errorf("type = %q, want k8s.pod.added", ce.eventType)
+		}
+	}
+}
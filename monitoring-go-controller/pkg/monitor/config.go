@@ -0,0 +1,525 @@
+package monitor
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config holds the settings that can be given on the command line. Every flag
+// falls back to an environment variable, then to a built-in default.
+type Config struct {
+	// ConfigFile is a YAML file of settings keyed by flag name. Flags override
+	// it and it overrides environment variables; SIGHUP re-reads it.
+	ConfigFile string
+	Namespaces []string
+	// AllNamespaces watches every namespace and overrides Namespaces.
+	AllNamespaces bool
+	Kubeconfig    string
+	// DirectAuth, when any of its options is set, is used instead of the
+	// in-cluster config and Kubeconfig.
+	DirectAuth DirectAuth
+	// ImpersonateUser, when set, makes every API request as this user or
+	// service account, with ImpersonateGroups as its groups. The monitor's
+	// own identity needs the impersonate verb for them.
+	ImpersonateUser   string
+	ImpersonateGroups []string
+	MaxRetries        int
+	// OnWatchFailure is "exit", giving up after MaxRetries consecutive
+	// failures, or "retry-forever".
+	OnWatchFailure string
+	// StablePeriod is how long a watch must stay open before its failure
+	// count is reset. Zero resets it only when events arrive.
+	StablePeriod time.Duration
+	// StrictRBAC fails startup when the monitor may not list and watch pods
+	// in a watched namespace, instead of only logging the missing rule.
+	StrictRBAC bool
+	// FieldSelector narrows the pod List and Watch calls, e.g.
+	// status.phase=Running. Empty selects every pod.
+	FieldSelector string
+	// MetricsAddr is the listen address for /metrics. Empty disables it.
+	MetricsAddr string
+	// HealthAddr is the listen address for /healthz and /readyz. Empty
+	// disables them.
+	HealthAddr string
+	// PendingThreshold is how long a pod may stay Pending before a
+	// POD_PENDING event is emitted. Zero disables the check.
+	PendingThreshold time.Duration
+	// ResyncPeriod re-emits every tracked pod as a SYNC event at this
+	// interval. Zero disables it.
+	ResyncPeriod time.Duration
+	// SinkHeartbeatInterval sends a HEALTH_SUMMARY to the connection-based
+	// sinks at this interval, whatever SUMMARY_INTERVAL is. Zero disables
+	// it.
+	SinkHeartbeatInterval time.Duration
+	// TerminatingSlack is how long past its grace period a pod may stay
+	// Terminating before a TERMINATING_STUCK event is emitted.
+	TerminatingSlack time.Duration
+	// WebhookURL receives every emitted event as a JSON POST. Empty
+	// disables the webhook sink.
+	WebhookURL string
+	// SlackWebhookURL receives warning-level events as Slack messages. Empty
+	// disables the Slack sink.
+	SlackWebhookURL string
+	// TeamsWebhookURL receives the same events as Slack as Microsoft Teams
+	// cards. Empty disables the Teams sink.
+	TeamsWebhookURL string
+	// WebhookCAFile is a CA bundle trusted, in addition to the system
+	// roots, by the webhook, Slack and Teams sinks.
+	// WebhookInsecureSkipVerify turns their certificate checks off.
+	WebhookCAFile             string
+	WebhookInsecureSkipVerify bool
+	// WatchMode selects the raw List+Watch loop ("watch") or a client-go
+	// shared informer ("informer").
+	WatchMode string
+	// LogFormat (json or text) and LogLevel configure the structured log
+	// output. LegacyLog restores the "[POD-MONITOR]" JSON-plus-emoji lines.
+	LogFormat string
+	LogLevel  string
+	LegacyLog bool
+	// Color (auto, always or never) colors human-readable event output.
+	// Table writes events as compact table rows instead of JSON lines.
+	Color string
+	Table bool
+	// SplitStreams writes warning-level events and log records to stderr
+	// and the rest to stdout.
+	SplitStreams bool
+	// DryRun lists the pods that would be monitored and exits instead of
+	// watching.
+	DryRun bool
+	// ShutdownTimeout bounds how long events still buffered at shutdown are
+	// flushed to the asynchronous sinks.
+	ShutdownTimeout time.Duration
+	// SchemaVersion selects the event JSON schema version.
+	SchemaVersion int
+	// Duration stops the monitor cleanly after it has run this long. Zero
+	// runs until a shutdown signal.
+	Duration time.Duration
+	// TimeFormat (rfc3339, unix or unixmilli) and Timezone control event
+	// and log timestamps. An empty Timezone keeps the local time zone.
+	TimeFormat string
+	Timezone   string
+	// OutputFormat is "json", writing events to the sinks as they are, or
+	// "cloudevents", wrapping them in CloudEvents 1.0 envelopes.
+	OutputFormat string
+	// DBPath is the SQLite database events are persisted to. Empty disables
+	// persistence.
+	DBPath string
+	// APIToken protects the event history API. Empty leaves it open.
+	APIToken string
+	// EventBufferSize is how many recent events are kept in memory for
+	// /events/recent. Zero disables the buffer.
+	EventBufferSize int
+	// ModifiedThrottle is the window in which uninformative MODIFIED events
+	// for the same pod are dropped. Zero disables throttling.
+	ModifiedThrottle time.Duration
+	// WatchStatefulSets and WatchDaemonSets emit rollout status events for
+	// those controllers in the watched namespaces.
+	WatchStatefulSets bool
+	WatchDaemonSets   bool
+	// WatchPVCs emits PVC_BOUND and PVC_LOST on claim phase changes and
+	// PVC_PENDING for claims Pending longer than PVCPendingThreshold.
+	WatchPVCs           bool
+	PVCPendingThreshold time.Duration
+	// TrackAnnotations reports annotation changes in MODIFIED reasons,
+	// except for the comma-separated IgnoreAnnotations keys.
+	TrackAnnotations  bool
+	IgnoreAnnotations string
+	// RestartAlertThreshold emits a RESTART_THRESHOLD event when a
+	// container's restart count passes it. Zero disables the alert.
+	RestartAlertThreshold int
+	// ListTimeout bounds each pod List call so a slow API server fails the
+	// attempt, which is retried with backoff, instead of hanging.
+	ListTimeout time.Duration
+	// RelistInterval periodically replaces the pod watch with a fresh list
+	// so pods whose deletion was never delivered are evicted. Zero disables
+	// it.
+	RelistInterval time.Duration
+	// Flap configures flapping-pod detection.
+	Flap flapDetection
+	// Backoff controls the wait between watch reconnect attempts.
+	Backoff reconnectBackoff
+	// LeaderElect makes replicas compete for a Lease so only the leader
+	// watches and emits events. LeaderElectionNamespace defaults to the
+	// namespace the controller runs in.
+	LeaderElect             bool
+	LeaderElectionLease     string
+	LeaderElectionNamespace string
+	// OutputFile receives every event as a JSON line, rotated once it
+	// reaches OutputFileMaxSizeMB with OutputFileMaxBackups old files kept.
+	// Empty disables the file sink.
+	OutputFile           string
+	OutputFileMaxSizeMB  int
+	OutputFileMaxBackups int
+	// KafkaBrokers (comma-separated host:port) and KafkaTopic configure the
+	// Kafka sink. Empty brokers disable it.
+	KafkaBrokers string
+	KafkaTopic   string
+	// RedisAddr (host:port) and RedisChannel configure the Redis pub/sub
+	// sink, RedisTLS connects to it over TLS. An empty address disables it.
+	RedisAddr    string
+	RedisChannel string
+	RedisTLS     bool
+	// NATSURL (nats:// or tls://), NATSSubject, NATSCreds, a credentials
+	// file, and NATSCA, a CA bundle for TLS, configure the NATS JetStream
+	// sink. An empty URL disables it.
+	NATSURL     string
+	NATSSubject string
+	NATSCreds   string
+	NATSCA      string
+	// OTelEndpoint is the OTLP/HTTP base URL spans and metrics are exported
+	// to. Empty disables OpenTelemetry export.
+	OTelEndpoint string
+	// EventTypes is a comma-separated subset of ADDED,MODIFIED,DELETED to
+	// emit; empty emits all. MinSeverity (info, warning or critical) drops pod events
+	// below it.
+	EventTypes  string
+	MinSeverity string
+	// ExcludeNamespaces (comma-separated) and ExcludePodRegex drop matching
+	// events; IncludePodRegex drops pod events whose name does not match.
+	ExcludeNamespaces string
+	ExcludePodRegex   string
+	IncludePodRegex   string
+	// OwnerKind and OwnerName limit the monitor to the pods of one
+	// Deployment, ReplicaSet, StatefulSet, DaemonSet or Job.
+	OwnerKind string
+	OwnerName string
+	// KubeQPS and KubeBurst set the client-side rate limit for Kubernetes API
+	// requests.
+	KubeQPS   float32
+	KubeBurst int
+	// WatchStrategy is "server_side", narrowing the pod watch on the API
+	// server, or "client_side", watching every namespace and filtering
+	// in-process.
+	WatchStrategy string
+	// WatchEvents emits PROBE_FAILED for kubelet probe failures and
+	// CorrelateEvents attaches the latest core/v1 Event to a pod's next
+	// event. WatchNodes emits NODE_* events.
+	WatchEvents     bool
+	CorrelateEvents bool
+	WatchNodes      bool
+	// PodCountInterval emits NS_POD_COUNTS and SummaryInterval a
+	// HEALTH_SUMMARY listing the SummaryTopN most restarted pods. Zero
+	// disables them.
+	PodCountInterval time.Duration
+	SummaryInterval  time.Duration
+	SummaryTopN      int
+	// ImportantLabel (key=value, or key for any value) marks important
+	// pods.
+	ImportantLabel string
+	// ClusterName is reported on lifecycle events and as the CloudEvents
+	// and OpenTelemetry source.
+	ClusterName string
+	// ServiceAccountFilter only emits events for pods running as this
+	// service account.
+	ServiceAccountFilter string
+	// TerminalLingerThreshold emits TERMINAL_LINGER for pods left Succeeded
+	// or Failed longer than this. Zero disables it.
+	TerminalLingerThreshold time.Duration
+	// ClockSkewTolerance is how far in the future an API timestamp may be
+	// before durations computed from it are not reported. Zero accepts any.
+	ClockSkewTolerance time.Duration
+	// EnableUsage queries metrics-server for USAGE events every
+	// UsageInterval.
+	EnableUsage   bool
+	UsageInterval time.Duration
+	// EnrichNodeLabels and EnrichServices add node zones and the Services
+	// selecting a pod to its events, from caches rebuilt every
+	// NodeLabelRefresh and ServiceRefresh.
+	EnrichNodeLabels bool
+	NodeLabelRefresh time.Duration
+	EnrichServices   bool
+	ServiceRefresh   time.Duration
+	// LogEvents writes events to stdout. EventChannelSize is the buffer of
+	// each Events() channel.
+	LogEvents        bool
+	EventChannelSize int
+
+	// args are the command-line arguments ParseFlags resolved the config
+	// from, kept so a reload resolves it the same way.
+	args []string
+}
+
+// Rate limits above these are accepted but logged as likely mistakes.
+const (
+	maxSensibleKubeQPS   = 500
+	maxSensibleKubeBurst = 1000
+)
+
+// DefaultConfig returns the configuration used when no flags are given:
+// environment variables, then built-in defaults. It fails when CONFIG_FILE
+// names an unreadable or invalid file.
+func DefaultConfig() (Config, error) {
+	cfg, _, err := parseConfig(nil, io.Discard)
+	return cfg, err
+}
+
+// ParseFlags parses the command line into a Config. It also reports whether
+// --health-check was requested. Unknown flags and malformed values print the
+// usage to stderr; -h and --help return flag.ErrHelp.
+func ParseFlags(args []string) (Config, bool, error) {
+	return parseConfig(args, os.Stderr)
+}
+
+// parseConfig resolves args, the config file and the environment into a
+// Config. Flag syntax errors and the usage are written to output.
+func parseConfig(args []string, output io.Writer) (Config, bool, error) {
+	cfg := Config{args: args}
+	var namespaces, asGroups string
+	var healthCheck bool
+
+	fs := flag.NewFlagSet("pod-monitor", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&cfg.ConfigFile, "config", os.Getenv("CONFIG_FILE"),
+		"read settings keyed by flag name from this file, re-read on SIGHUP (env CONFIG_FILE)")
+	fs.StringVar(&namespaces, "namespace", namespaceFromEnv(),
+		"comma-separated namespaces to watch (env NAMESPACE)")
+	fs.BoolVar(&cfg.AllNamespaces, "all-namespaces", envBool("ALL_NAMESPACES", false),
+		"watch pods in every namespace, ignoring --namespace (env ALL_NAMESPACES)")
+	fs.StringVar(&cfg.FieldSelector, "field-selector", os.Getenv("FIELD_SELECTOR"),
+		"field selector applied to the pod list and watch, e.g. status.phase=Running (env FIELD_SELECTOR)")
+	fs.StringVar(&cfg.WatchMode, "watch-mode", envString("WATCH_MODE", watchModeWatch),
+		"how pods are watched: watch (raw List+Watch) or informer (env WATCH_MODE)")
+	fs.StringVar(&cfg.Kubeconfig, "kubeconfig", os.Getenv("KUBECONFIG"),
+		"kubeconfig used when not running in-cluster (env KUBECONFIG, default ~/.kube/config)")
+	fs.StringVar(&cfg.DirectAuth.APIServer, "api-server", os.Getenv("API_SERVER"),
+		"connect to this API server URL directly instead of in-cluster or kubeconfig (env API_SERVER)")
+	fs.StringVar(&cfg.DirectAuth.Token, "token", os.Getenv("KUBE_TOKEN"),
+		"bearer token for --api-server (env KUBE_TOKEN)")
+	fs.StringVar(&cfg.DirectAuth.ClientCert, "client-cert", os.Getenv("CLIENT_CERT"),
+		"client certificate file for --api-server, with --client-key (env CLIENT_CERT)")
+	fs.StringVar(&cfg.DirectAuth.ClientKey, "client-key", os.Getenv("CLIENT_KEY"),
+		"client key file for --api-server (env CLIENT_KEY)")
+	fs.StringVar(&cfg.DirectAuth.CACert, "ca-cert", os.Getenv("CA_CERT"),
+		"CA certificate file used to verify --api-server, default system roots (env CA_CERT)")
+	fs.StringVar(&cfg.ImpersonateUser, "as", os.Getenv("IMPERSONATE_USER"),
+		"impersonate this user or service account (system:serviceaccount:<namespace>:<name>) for API requests (env IMPERSONATE_USER)")
+	fs.StringVar(&asGroups, "as-group", os.Getenv("IMPERSONATE_GROUPS"),
+		"comma-separated groups to impersonate, with --as (env IMPERSONATE_GROUPS)")
+	fs.IntVar(&cfg.MaxRetries, "max-retries", envInt("MAX_RETRIES", 10),
+		"consecutive watch failures before giving up (env MAX_RETRIES)")
+	fs.StringVar(&cfg.OnWatchFailure, "on-watch-failure", envString("ON_WATCH_FAILURE", watchFailureExit),
+		"what a pod watch does after --max-retries consecutive failures: exit, or retry-forever with capped backoff (env ON_WATCH_FAILURE)")
+	fs.DurationVar(&cfg.StablePeriod, "stable-period", envDuration("STABLE_PERIOD", time.Minute),
+		"reset a pod watch's failure count once it has stayed open this long, 0 to reset only on events (env STABLE_PERIOD)")
+	fs.BoolVar(&cfg.StrictRBAC, "strict-rbac", envBool("STRICT_RBAC", false),
+		"exit at startup when pods cannot be listed and watched in a watched namespace (env STRICT_RBAC)")
+	fs.StringVar(&cfg.MetricsAddr, "metrics-addr", envString("METRICS_ADDR", ":8080"),
+		"listen address for the Prometheus /metrics endpoint, empty to disable (env METRICS_ADDR)")
+	fs.StringVar(&cfg.HealthAddr, "health-addr", os.Getenv("HEALTH_ADDR"),
+		"listen address for the /healthz and /readyz endpoints, empty to disable (env HEALTH_ADDR)")
+	fs.DurationVar(&cfg.PendingThreshold, "pending-threshold", envDuration("PENDING_THRESHOLD", 5*time.Minute),
+		"emit POD_PENDING once for pods Pending longer than this, 0 to disable (env PENDING_THRESHOLD)")
+	fs.DurationVar(&cfg.ResyncPeriod, "resync-period", envDuration("RESYNC_PERIOD", 0),
+		"re-emit every tracked pod as a SYNC event at this interval, 0 to disable (env RESYNC_PERIOD)")
+	fs.DurationVar(&cfg.SinkHeartbeatInterval, "sink-heartbeat-interval", envDuration("SINK_HEARTBEAT_INTERVAL", 0),
+		"send a HEALTH_SUMMARY heartbeat to the Kafka, NATS, Redis, Loki and webhook sinks at this interval to keep their connections warm, 0 to disable (env SINK_HEARTBEAT_INTERVAL)")
+	fs.DurationVar(&cfg.TerminatingSlack, "terminating-slack", envDuration("TERMINATING_SLACK", 0),
+		"extra time past its grace period a pod may stay Terminating before TERMINATING_STUCK (env TERMINATING_SLACK)")
+	fs.StringVar(&cfg.WebhookURL, "webhook-url", os.Getenv("WEBHOOK_URL"),
+		"POST every event as JSON to this URL (env WEBHOOK_URL)")
+	fs.StringVar(&cfg.SlackWebhookURL, "slack-webhook-url", os.Getenv("SLACK_WEBHOOK_URL"),
+		"post warning-level events to this Slack incoming webhook (env SLACK_WEBHOOK_URL)")
+	fs.StringVar(&cfg.TeamsWebhookURL, "teams-webhook-url", os.Getenv("TEAMS_WEBHOOK_URL"),
+		"post warning-level events to this Microsoft Teams incoming webhook (env TEAMS_WEBHOOK_URL)")
+	fs.StringVar(&cfg.WebhookCAFile, "webhook-ca-file", os.Getenv("WEBHOOK_CA_FILE"),
+		"CA certificate file trusted in addition to the system roots by the webhook, Slack and Teams sinks (env WEBHOOK_CA_FILE)")
+	fs.BoolVar(&cfg.WebhookInsecureSkipVerify, "webhook-insecure-skip-verify", envBool("WEBHOOK_INSECURE_SKIP_VERIFY", false),
+		"do not verify the TLS certificates of the webhook, Slack and Teams endpoints, for development only (env WEBHOOK_INSECURE_SKIP_VERIFY)")
+	fs.StringVar(&cfg.LogFormat, "log-format", envString("LOG_FORMAT", logFormatJSON),
+		"structured log format: json or text (env LOG_FORMAT)")
+	fs.StringVar(&cfg.LogLevel, "log-level", envString("LOG_LEVEL", "info"),
+		"minimum log level: debug, info, warn or error (env LOG_LEVEL)")
+	fs.BoolVar(&cfg.LegacyLog, "log-legacy", envBool("LOG_LEGACY", false),
+		"write the original JSON line plus emoji line per event instead of structured logs (env LOG_LEGACY)")
+	fs.BoolVar(&cfg.DryRun, "dry-run", envBool("DRY_RUN", false),
+		"list the pods that would be monitored with the current filters and exit (env DRY_RUN)")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", envDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		"how long to flush buffered events to the sinks on shutdown (env SHUTDOWN_TIMEOUT)")
+	fs.IntVar(&cfg.SchemaVersion, "schema-version", envInt("SCHEMA_VERSION", schemaVersion1),
+		"event JSON schema version to write (env SCHEMA_VERSION)")
+	fs.DurationVar(&cfg.Duration, "duration", envDuration("DURATION", 0),
+		"watch for this long, log a summary and exit 0, e.g. 10m; 0 runs until stopped (env DURATION)")
+	fs.StringVar(&cfg.Color, "color", envString("COLOR", colorAuto),
+		"color human-readable event output: auto (when stdout is a terminal), always or never (env COLOR)")
+	fs.BoolVar(&cfg.Table, "table", envBool("TABLE", false),
+		"write events as a compact table instead of JSON lines, for interactive use (env TABLE)")
+	fs.BoolVar(&cfg.SplitStreams, "split-streams", envBool("SPLIT_STREAMS", false),
+		"write warning-level events and logs to stderr and the rest to stdout (env SPLIT_STREAMS)")
+	fs.StringVar(&cfg.TimeFormat, "time-format", envString("TIME_FORMAT", timeFormatRFC3339),
+		"event and log timestamp format: rfc3339, unix or unixmilli (env TIME_FORMAT)")
+	fs.StringVar(&cfg.Timezone, "timezone", os.Getenv("TIMEZONE"),
+		"time zone for event and log timestamps, e.g. UTC or Europe/Berlin; empty for local time (env TIMEZONE)")
+	fs.StringVar(&cfg.OutputFormat, "output-format", envString("OUTPUT_FORMAT", outputFormatJSON),
+		"JSON shape of events sent to the sinks: json, or cloudevents for CloudEvents 1.0 envelopes (env OUTPUT_FORMAT)")
+	fs.DurationVar(&cfg.Backoff.initial, "backoff-initial", envDuration("BACKOFF_INITIAL", time.Second),
+		"upper bound of the first reconnect wait (env BACKOFF_INITIAL)")
+	fs.Float64Var(&cfg.Backoff.factor, "backoff-factor", envFloat("BACKOFF_FACTOR", 2),
+		"growth factor of the reconnect wait per consecutive failure (env BACKOFF_FACTOR)")
+	fs.DurationVar(&cfg.Backoff.max, "backoff-max", envDuration("BACKOFF_MAX", 30*time.Second),
+		"cap on the reconnect wait (env BACKOFF_MAX)")
+	fs.StringVar(&cfg.DBPath, "db-path", os.Getenv("DB_PATH"),
+		"persist events to this SQLite database (env DB_PATH)")
+	fs.StringVar(&cfg.APIToken, "api-token", os.Getenv("API_TOKEN"),
+		"bearer token required by GET /events (env API_TOKEN)")
+	fs.IntVar(&cfg.EventBufferSize, "event-buffer-size", envInt("EVENT_BUFFER_SIZE", 1000),
+		"recent events kept in memory for /events/recent, 0 to disable (env EVENT_BUFFER_SIZE)")
+	fs.DurationVar(&cfg.ModifiedThrottle, "modified-throttle", envDuration("MODIFIED_THROTTLE", 2*time.Second),
+		"drop repeated or metadata-only MODIFIED events for a pod within this window, 0 to disable (env MODIFIED_THROTTLE)")
+	fs.BoolVar(&cfg.WatchStatefulSets, "watch-statefulsets", envBool("WATCH_STATEFULSETS", false),
+		"emit STATEFULSET_ROLLOUT events on revision and ready replica changes (env WATCH_STATEFULSETS)")
+	fs.BoolVar(&cfg.WatchDaemonSets, "watch-daemonsets", envBool("WATCH_DAEMONSETS", false),
+		"emit DAEMONSET_ROLLOUT events on ready, updated and unavailable pod changes (env WATCH_DAEMONSETS)")
+	fs.BoolVar(&cfg.WatchPVCs, "watch-pvcs", envBool("WATCH_PVCS", false),
+		"watch PersistentVolumeClaims and emit PVC_PENDING, PVC_BOUND and PVC_LOST events (env WATCH_PVCS)")
+	fs.DurationVar(&cfg.PVCPendingThreshold, "pvc-pending-threshold", envDuration("PVC_PENDING_THRESHOLD", 5*time.Minute),
+		"emit PVC_PENDING once for claims Pending longer than this, 0 to disable (env PVC_PENDING_THRESHOLD)")
+	fs.BoolVar(&cfg.TrackAnnotations, "track-annotations", envBool("TRACK_ANNOTATIONS", false),
+		"report added, removed and changed annotations in MODIFIED reasons (env TRACK_ANNOTATIONS)")
+	fs.StringVar(&cfg.IgnoreAnnotations, "ignore-annotations", envString("IGNORE_ANNOTATIONS", defaultIgnoredAnnotations),
+		"comma-separated annotation keys --track-annotations skips (env IGNORE_ANNOTATIONS)")
+	fs.IntVar(&cfg.RestartAlertThreshold, "restart-alert-threshold", envInt("RESTART_ALERT_THRESHOLD", 0),
+		"emit a critical RESTART_THRESHOLD event once when a container's restart count passes this, 0 to disable (env RESTART_ALERT_THRESHOLD)")
+	fs.DurationVar(&cfg.ListTimeout, "list-timeout", envDuration("LIST_TIMEOUT", 30*time.Second),
+		"timeout of each pod List call; a timed out list is retried with backoff (env LIST_TIMEOUT)")
+	fs.DurationVar(&cfg.RelistInterval, "relist-interval", envDuration("RELIST_INTERVAL", 30*time.Minute),
+		"relist pods at this interval to reconcile missed deletions, 0 to disable (env RELIST_INTERVAL)")
+	fs.IntVar(&cfg.Flap.restarts, "flap-restarts", envInt("FLAP_RESTARTS", 0),
+		"restarts within --flap-window that mark a pod as flapping, 0 (default) to disable (env FLAP_RESTARTS)")
+	fs.DurationVar(&cfg.Flap.window, "flap-window", envDuration("FLAP_WINDOW", 5*time.Minute),
+		"window for counting restarts (env FLAP_WINDOW)")
+	fs.DurationVar(&cfg.Flap.cooldown, "flap-cooldown", envDuration("FLAP_COOLDOWN", 10*time.Minute),
+		"how long restart updates of a flapping pod are suppressed (env FLAP_COOLDOWN)")
+	fs.BoolVar(&cfg.LeaderElect, "leader-elect", envBool("LEADER_ELECT", false),
+		"only watch and emit events while holding the leader lease (env LEADER_ELECT)")
+	fs.StringVar(&cfg.LeaderElectionLease, "leader-election-lease", envString("LEADER_ELECTION_LEASE", "pod-monitor"),
+		"name of the leader election Lease (env LEADER_ELECTION_LEASE)")
+	fs.StringVar(&cfg.LeaderElectionNamespace, "leader-election-namespace", os.Getenv("LEADER_ELECTION_NAMESPACE"),
+		"namespace of the leader election Lease, default the controller's own namespace (env LEADER_ELECTION_NAMESPACE)")
+	fs.StringVar(&cfg.OutputFile, "output-file", os.Getenv("OUTPUT_FILE"),
+		"append every event as a JSON line to this file (env OUTPUT_FILE)")
+	fs.IntVar(&cfg.OutputFileMaxSizeMB, "output-file-max-size", envInt("OUTPUT_FILE_MAX_SIZE", 100),
+		"rotate the output file once it reaches this many megabytes, 0 to never rotate (env OUTPUT_FILE_MAX_SIZE)")
+	fs.IntVar(&cfg.OutputFileMaxBackups, "output-file-max-backups", envInt("OUTPUT_FILE_MAX_BACKUPS", 5),
+		"rotated output files to keep (env OUTPUT_FILE_MAX_BACKUPS)")
+	fs.StringVar(&cfg.KafkaBrokers, "kafka-brokers", os.Getenv("KAFKA_BROKERS"),
+		"produce every event as JSON to these comma-separated Kafka brokers (env KAFKA_BROKERS)")
+	fs.StringVar(&cfg.KafkaTopic, "kafka-topic", envString("KAFKA_TOPIC", "pod-events"),
+		"Kafka topic events are produced to (env KAFKA_TOPIC)")
+	fs.StringVar(&cfg.RedisAddr, "redis-addr", os.Getenv("REDIS_ADDR"),
+		"publish every event as JSON to Redis at this host:port (env REDIS_ADDR)")
+	fs.StringVar(&cfg.RedisChannel, "redis-channel", envString("REDIS_CHANNEL", "pod-events"),
+		"Redis pub/sub channel events are published to (env REDIS_CHANNEL)")
+	fs.BoolVar(&cfg.RedisTLS, "redis-tls", envBool("REDIS_TLS", false),
+		"connect to Redis over TLS (env REDIS_TLS)")
+	fs.StringVar(&cfg.NATSURL, "nats-url", os.Getenv("NATS_URL"),
+		"publish every event as JSON to NATS JetStream at this URL, e.g. nats://nats:4222 (env NATS_URL)")
+	fs.StringVar(&cfg.NATSSubject, "nats-subject", envString("NATS_SUBJECT", "pod-events"),
+		"JetStream subject events are published to (env NATS_SUBJECT)")
+	fs.StringVar(&cfg.NATSCreds, "nats-creds", os.Getenv("NATS_CREDS"),
+		"NATS credentials file used to authenticate (env NATS_CREDS)")
+	fs.StringVar(&cfg.NATSCA, "nats-ca", os.Getenv("NATS_CA"),
+		"CA certificate file used to verify the NATS server over TLS, default system roots (env NATS_CA)")
+	fs.StringVar(&cfg.OTelEndpoint, "otel-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		"export spans and metrics via OTLP/HTTP to this collector, e.g. http://otel-collector:4318 (env OTEL_EXPORTER_OTLP_ENDPOINT)")
+	fs.StringVar(&cfg.EventTypes, "event-types", os.Getenv("EVENT_TYPES"),
+		"comma-separated subset of ADDED,MODIFIED,DELETED to emit, empty for all (env EVENT_TYPES)")
+	fs.StringVar(&cfg.MinSeverity, "min-severity", envString("MIN_SEVERITY", "info"),
+		"drop pod events below this severity: info, warning or critical (env MIN_SEVERITY)")
+	fs.StringVar(&cfg.ExcludeNamespaces, "exclude-namespaces", os.Getenv("EXCLUDE_NAMESPACES"),
+		"comma-separated namespaces whose events are dropped, e.g. kube-system (env EXCLUDE_NAMESPACES)")
+	fs.StringVar(&cfg.ExcludePodRegex, "exclude-pod-regex", os.Getenv("EXCLUDE_POD_REGEX"),
+		"drop events for pods whose name matches this regular expression (env EXCLUDE_POD_REGEX)")
+	fs.StringVar(&cfg.IncludePodRegex, "include-pod-regex", os.Getenv("INCLUDE_POD_REGEX"),
+		"only emit events for pods whose name matches this regular expression (env INCLUDE_POD_REGEX)")
+	fs.StringVar(&cfg.OwnerKind, "owner-kind", os.Getenv("OWNER_KIND"),
+		"only watch pods of this kind of workload: Deployment, ReplicaSet, StatefulSet, DaemonSet or Job (env OWNER_KIND)")
+	fs.StringVar(&cfg.OwnerName, "owner-name", os.Getenv("OWNER_NAME"),
+		"name of the --owner-kind workload whose pods are watched (env OWNER_NAME)")
+	fs.StringVar(&cfg.WatchStrategy, "watch-strategy", envString("WATCH_STRATEGY", watchStrategyServerSide),
+		"where the pod watch is narrowed: server_side or client_side (env WATCH_STRATEGY)")
+	fs.BoolVar(&cfg.WatchEvents, "watch-events", envBool("WATCH_EVENTS", false),
+		"emit PROBE_FAILED for kubelet probe failures (env WATCH_EVENTS)")
+	fs.BoolVar(&cfg.CorrelateEvents, "correlate-events", envBool("CORRELATE_EVENTS", false),
+		"attach the latest Kubernetes event to a pod's next event (env CORRELATE_EVENTS)")
+	fs.BoolVar(&cfg.WatchNodes, "watch-nodes", envBool("WATCH_NODES", false),
+		"emit NODE_* events for node readiness, cordon and pressure changes (env WATCH_NODES)")
+	fs.DurationVar(&cfg.PodCountInterval, "pod-count-interval", envDuration("POD_COUNT_INTERVAL", 0),
+		"emit NS_POD_COUNTS at this interval, 0 to disable (env POD_COUNT_INTERVAL)")
+	fs.DurationVar(&cfg.SummaryInterval, "summary-interval", envDuration("SUMMARY_INTERVAL", 0),
+		"emit HEALTH_SUMMARY at this interval, 0 to disable (env SUMMARY_INTERVAL)")
+	fs.IntVar(&cfg.SummaryTopN, "summary-top-n", envInt("SUMMARY_TOP_N", 5),
+		"pods with the most restarts listed in HEALTH_SUMMARY (env SUMMARY_TOP_N)")
+	fs.StringVar(&cfg.ImportantLabel, "important-label", os.Getenv("IMPORTANT_LABEL"),
+		"label (key=value or key) marking important pods (env IMPORTANT_LABEL)")
+	fs.StringVar(&cfg.ClusterName, "cluster-name", os.Getenv("CLUSTER_NAME"),
+		"cluster name reported on lifecycle events and as the event source (env CLUSTER_NAME)")
+	fs.StringVar(&cfg.ServiceAccountFilter, "service-account-filter", os.Getenv("SERVICE_ACCOUNT_FILTER"),
+		"only emit events for pods running as this service account (env SERVICE_ACCOUNT_FILTER)")
+	fs.DurationVar(&cfg.TerminalLingerThreshold, "terminal-linger-threshold", envDuration("TERMINAL_LINGER_THRESHOLD", 0),
+		"emit TERMINAL_LINGER for pods left Succeeded or Failed this long, 0 to disable (env TERMINAL_LINGER_THRESHOLD)")
+	fs.DurationVar(&cfg.ClockSkewTolerance, "clock-skew-tolerance", envDuration("CLOCK_SKEW_TOLERANCE", 0),
+		"drop durations whose timestamp is further in the future than this, 0 to keep them (env CLOCK_SKEW_TOLERANCE)")
+	fs.BoolVar(&cfg.EnableUsage, "enable-usage", envBool("ENABLE_USAGE", false),
+		"emit USAGE events from metrics-server (env ENABLE_USAGE)")
+	fs.DurationVar(&cfg.UsageInterval, "usage-interval", envDuration("USAGE_INTERVAL", time.Minute),
+		"how often USAGE events are emitted (env USAGE_INTERVAL)")
+	fs.BoolVar(&cfg.EnrichNodeLabels, "enrich-node-labels", envBool("ENRICH_NODE_LABELS", false),
+		"add the node's zone and instance type to pod events (env ENRICH_NODE_LABELS)")
+	fs.DurationVar(&cfg.NodeLabelRefresh, "node-label-refresh", envDuration("NODE_LABEL_REFRESH", 5*time.Minute),
+		"how often the node label cache is rebuilt (env NODE_LABEL_REFRESH)")
+	fs.BoolVar(&cfg.EnrichServices, "enrich-services", envBool("ENRICH_SERVICES", false),
+		"tie pod readiness changes to the Services selecting the pod (env ENRICH_SERVICES)")
+	fs.DurationVar(&cfg.ServiceRefresh, "service-refresh", envDuration("SERVICE_REFRESH", time.Minute),
+		"how often the Service selector cache is rebuilt (env SERVICE_REFRESH)")
+	fs.BoolVar(&cfg.LogEvents, "log-events", envBool("LOG_EVENTS", true),
+		"write events to stdout (env LOG_EVENTS)")
+	fs.IntVar(&cfg.EventChannelSize, "event-channel-size", envInt("EVENT_CHANNEL_SIZE", 256),
+		"buffer size of each Events() channel (env EVENT_CHANNEL_SIZE)")
+	kubeQPS := fs.Float64("kube-qps", envFloat("KUBE_QPS", 20),
+		"sustained Kubernetes API requests per second (env KUBE_QPS)")
+	fs.IntVar(&cfg.KubeBurst, "kube-burst", envInt("KUBE_BURST", 30),
+		"Kubernetes API request burst above --kube-qps (env KUBE_BURST)")
+	fs.BoolVar(&healthCheck, "health-check", false,
+		"check connectivity to the Kubernetes API and exit")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: pod-monitor [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Settings are resolved in this order: command-line flag, --config file,\n")
+		fmt.Fprintf(fs.Output(), "environment variable, built-in default. Options without a flag are\n")
+		fmt.Fprintf(fs.Output(), "read from the environment only.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, false, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, false, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	// The file is applied over the environment defaults, then the command
+	// line is parsed again so flags take precedence.
+	if cfg.ConfigFile != "" {
+		if err := applyConfigFile(fs, cfg.ConfigFile); err != nil {
+			return Config{}, false, err
+		}
+		if err := fs.Parse(args); err != nil {
+			return Config{}, false, err
+		}
+	}
+
+	cfg.KubeQPS = float32(*kubeQPS)
+	for _, group := range strings.Split(asGroups, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			cfg.ImpersonateGroups = append(cfg.ImpersonateGroups, group)
+		}
+	}
+	if !cfg.AllNamespaces {
+		cfg.Namespaces = strings.Split(namespaces, ",")
+	}
+	return cfg, healthCheck, nil
+}
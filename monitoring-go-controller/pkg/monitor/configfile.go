@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configSetting is one top-level key of the --config file.
+type configSetting struct {
+	line   int
+	column int
+	key    string
+	value  string
+}
+
+// readConfigFile reads a YAML mapping whose keys are flag names. Values are
+// scalars, or lists of scalars for comma-separated flags such as namespace;
+// an empty value or null sets the flag to the empty string.
+func readConfigFile(path string) ([]configSetting, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%s: invalid YAML: %v", path, err)
+	}
+	// An empty file sets nothing.
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s:%d:%d: expected a mapping of flag names to values", path, root.Line, root.Column)
+	}
+
+	var settings []configSetting
+	seen := make(map[string]int)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode, valueNode := root.Content[i], root.Content[i+1]
+		if keyNode.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("%s:%d:%d: setting names must be plain strings", path, keyNode.Line, keyNode.Column)
+		}
+		key := keyNode.Value
+		if line, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%s:%d:%d: %s is already set on line %d", path, keyNode.Line, keyNode.Column, key, line)
+		}
+		seen[key] = keyNode.Line
+
+		value, err := configValue(valueNode)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d:%d: %s: %v", path, valueNode.Line, valueNode.Column, key, err)
+		}
+		settings = append(settings, configSetting{line: keyNode.Line, column: keyNode.Column, key: key, value: value})
+	}
+	return settings, nil
+}
+
+// configValue flattens a setting's value into the string form its flag
+// parses.
+func configValue(node *yaml.Node) (string, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return "", nil
+		}
+		return node.Value, nil
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", fmt.Errorf("list items must be plain values")
+			}
+			items = append(items, item.Value)
+		}
+		return strings.Join(items, ","), nil
+	}
+	return "", fmt.Errorf("expected a value or a list of values, not a mapping")
+}
+
+// applyConfigFile sets the flags named in the config file at path. Every
+// value is validated, even one the command line overrides afterwards.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	settings, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range settings {
+		if setting.key == "config" || setting.key == "health-check" || fs.Lookup(setting.key) == nil {
+			return fmt.Errorf("%s:%d:%d: unknown setting %q%s", path, setting.line, setting.column, setting.key, suggestFlag(fs, setting.key))
+		}
+		if err := fs.Set(setting.key, setting.value); err != nil {
+			return fmt.Errorf("%s:%d:%d: invalid value %q for %s: %v", path, setting.line, setting.column, setting.value, setting.key, err)
+		}
+	}
+	return nil
+}
+
+// suggestFlag names the flag an unknown key was probably meant to be, for
+// keys written as min_severity or minSeverity instead of min-severity.
+func suggestFlag(fs *flag.FlagSet, key string) string {
+	normalize := func(name string) string {
+		return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	}
+	suggestion := ""
+	fs.VisitAll(func(f *flag.Flag) {
+		if normalize(f.Name) == normalize(key) {
+			suggestion = fmt.Sprintf(" (did you mean %q?)", f.Name)
+		}
+	})
+	return suggestion
+}
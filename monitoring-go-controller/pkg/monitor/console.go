@@ -0,0 +1,128 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+// useColor resolves --color. auto colors only when out is a terminal and
+// NO_COLOR is not set.
+func useColor(mode string, out *os.File) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto, "":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		info, err := out.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, fmt.Errorf("invalid color mode %q: must be %s, %s or %s", mode, colorAuto, colorAlways, colorNever)
+}
+
+// eventColor picks the ANSI color of an event's human-readable line: red for
+// deletions and anything logged at WARN or above, green for new pods and
+// yellow for updates.
+func eventColor(event PodEvent) string {
+	switch {
+	case eventLevel(event) >= slog.LevelWarn || event.EventType == "DELETED":
+		return ansiRed
+	case event.EventType == "ADDED":
+		return ansiGreen
+	case event.EventType == "MODIFIED":
+		return ansiYellow
+	}
+	return ansiCyan
+}
+
+func colorize(color, text string) string {
+	if color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+// tableSink writes one compact, column-aligned row per event, for watching
+// the monitor in a terminal. Rows for events at WARN and above go to errOut,
+// which is out unless --split-streams is set.
+type tableSink struct {
+	out, errOut io.Writer
+	color       bool
+
+	mu      sync.Mutex
+	started bool
+}
+
+const tableRowFormat = "%-8s  %-19s  %-20s  %-40s  %s\n"
+
+func (s *tableSink) Emit(event PodEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		fmt.Fprintf(s.out, tableRowFormat, "TIME", "EVENT", "NAMESPACE", "OBJECT", "DETAILS")
+		s.started = true
+	}
+
+	details := event.Reason
+	if details == "" {
+		details = event.Message
+	}
+	row := fmt.Sprintf(tableRowFormat,
+		event.Timestamp.Format(time.TimeOnly), event.EventType, truncate(event.Namespace, 20), truncate(tableObject(event), 40), details)
+
+	out := s.out
+	if eventLevel(event) >= slog.LevelWarn {
+		out = s.errOut
+	}
+	if s.color {
+		row = colorize(eventColor(event), strings.TrimSuffix(row, "\n")) + "\n"
+	}
+	_, err := io.WriteString(out, row)
+	return err
+}
+
+// tableObject names what an event is about: the pod, workload, claim or
+// node.
+func tableObject(event PodEvent) string {
+	switch {
+	case event.PodName != "":
+		return event.PodName
+	case event.Workload != "":
+		return event.Workload
+	case event.PVC != nil:
+		return "pvc/" + event.PVC.Name
+	case event.NodeName != "":
+		return "node/" + event.NodeName
+	}
+	return "-"
+}
+
+func truncate(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	return s[:width-1] + "…"
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// debugState is the GET /debug/state response: what each pod watcher
+// currently tracks.
+type debugState struct {
+	Count    int            `json:"count"`
+	Watchers []watcherState `json:"watchers"`
+}
+
+type watcherState struct {
+	Namespace string `json:"namespace"`
+	// ResourceVersion is the last resourceVersion observed from the list,
+	// a watch event or a bookmark; a reconnect resumes from it.
+	ResourceVersion string       `json:"resource_version"`
+	Ready           bool         `json:"ready"`
+	Count           int          `json:"count"`
+	Pods            []trackedPod `json:"pods"`
+}
+
+type trackedPod struct {
+	UID             string           `json:"uid"`
+	Name            string           `json:"name"`
+	Namespace       string           `json:"namespace"`
+	Phase           string           `json:"phase"`
+	PhaseSince      *time.Time       `json:"phase_since,omitempty"`
+	ResourceVersion string           `json:"resource_version"`
+	Terminating     bool             `json:"terminating,omitempty"`
+	Restarts        map[string]int32 `json:"restarts,omitempty"`
+}
+
+func (p *trackedPod) addRestarts(container string, restarts int32) {
+	if p.Restarts == nil {
+		p.Restarts = make(map[string]int32)
+	}
+	p.Restarts[container] = restarts
+}
+
+// state snapshots the tracked pods under the watcher's lock, so it is
+// consistent with the watch loop's writes.
+func (w *podWatcher) state() watcherState {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	state := watcherState{
+		Namespace:       w.label(),
+		ResourceVersion: w.resourceVersion,
+		Ready:           w.ready.Load(),
+		Count:           len(w.existingPods),
+		Pods:            make([]trackedPod, 0, len(w.existingPods)),
+	}
+	if w.informerVersion != nil {
+		state.ResourceVersion = w.informerVersion()
+	}
+	for uid, pod := range w.existingPods {
+		tracked := trackedPod{
+			UID:             uid,
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			Phase:           string(pod.Status.Phase),
+			ResourceVersion: pod.ResourceVersion,
+			Terminating:     pod.DeletionTimestamp != nil,
+		}
+		if since, ok := w.phaseSince[uid]; ok {
+			tracked.PhaseSince = &since
+		}
+		for _, status := range pod.Status.InitContainerStatuses {
+			tracked.addRestarts("init:"+status.Name, status.RestartCount)
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			tracked.addRestarts(status.Name, status.RestartCount)
+		}
+		state.Pods = append(state.Pods, tracked)
+	}
+	sort.Slice(state.Pods, func(i, j int) bool {
+		if state.Pods[i].Namespace != state.Pods[j].Namespace {
+			return state.Pods[i].Namespace < state.Pods[j].Namespace
+		}
+		return state.Pods[i].Name < state.Pods[j].Name
+	})
+	return state
+}
+
+// handleDebugState serves the tracked pods of every watcher, for comparing
+// the monitor's view with the cluster.
+func (pm *PodMonitor) handleDebugState(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rw.Header().Set("Allow", http.MethodGet)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !pm.authorized(r) {
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	watchers := pm.podWatchers()
+	state := debugState{Watchers: make([]watcherState, 0, len(watchers))}
+	for _, w := range watchers {
+		watcher := w.state()
+		state.Count += watcher.Count
+		state.Watchers = append(state.Watchers, watcher)
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(state)
+}
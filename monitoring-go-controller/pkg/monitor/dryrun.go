@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dryRunSampleSize is how many pod names a dry run prints per namespace.
+const dryRunSampleSize = 10
+
+// DryRun builds the monitor from cfg exactly as a real run would, lists the
+// pods each watcher would track and reports how many of them the filters
+// let through, then exits without watching. List errors and missing RBAC
+// permissions to list or watch pods exit with status 1.
+func DryRun(cfg Config) {
+	monitor, err := NewPodMonitor(cfg)
+	if err != nil {
+		log.Printf("Dry run failed: unable to create monitor: %v", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := monitor.checkPodAccess(ctx); err != nil {
+		log.Printf("Dry run failed: %v", err)
+		os.Exit(1)
+	}
+
+	total := 0
+	for _, w := range monitor.watchers {
+		pods, err := monitor.clientset.CoreV1().Pods(w.namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: monitor.fieldSelector,
+		})
+		if err != nil {
+			log.Printf("Dry run failed: unable to list pods in namespace %s: %v", w.label(), explainImpersonation(err, monitor.impersonate))
+			os.Exit(1)
+		}
+
+		tracked, excluded := 0, 0
+		var sample []string
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if !monitor.inScope(pod) {
+				continue
+			}
+			tracked++
+			if event := monitor.newPodEvent("ADDED", pod); !event.Important && monitor.podExcluded(event) {
+				excluded++
+				continue
+			}
+			if len(sample) < dryRunSampleSize {
+				sample = append(sample, pod.Namespace+"/"+pod.Name)
+			}
+		}
+		total += tracked - excluded
+
+		fmt.Printf("Namespace %s: %d pods would be tracked, %d of them excluded by filters, events emitted for %d\n",
+			w.label(), tracked, excluded, tracked-excluded)
+		if len(sample) > 0 {
+			fmt.Printf("  e.g. %s\n", strings.Join(sample, ", "))
+		}
+	}
+
+	fmt.Printf("Dry run passed: events would be emitted for %d pods\n", total)
+	os.Exit(0)
+}
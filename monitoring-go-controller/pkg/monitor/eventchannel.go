@@ -0,0 +1,79 @@
+package monitor
+
+import "context"
+
+// eventChannelSize returns the Events() buffer size for --event-channel-size,
+// which is at least 1.
+func eventChannelSize(size int) int {
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+// Events returns a channel receiving every emitted event until ctx is
+// cancelled or the monitor stops, at which point the channel is closed. Each
+// call returns a new channel, buffered to --event-channel-size; events emitted
+// before the call are not delivered. Delivery never blocks: when a
+// consumer's buffer is full the event is dropped for that channel only and
+// counted. Called after the monitor has stopped, Events returns a closed
+// channel.
+func (pm *PodMonitor) Events(ctx context.Context) <-chan PodEvent {
+	ch := make(chan PodEvent, pm.eventChannelSize)
+
+	pm.subscribersMu.Lock()
+	defer pm.subscribersMu.Unlock()
+	if pm.subscribersClosed {
+		close(ch)
+		return ch
+	}
+	pm.subscribers[ch] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			pm.unsubscribe(ch)
+		case <-pm.eventsDone:
+		}
+	}()
+	return ch
+}
+
+func (pm *PodMonitor) unsubscribe(ch chan PodEvent) {
+	pm.subscribersMu.Lock()
+	defer pm.subscribersMu.Unlock()
+	if _, ok := pm.subscribers[ch]; ok {
+		delete(pm.subscribers, ch)
+		close(ch)
+	}
+}
+
+// closeEvents closes every Events() channel once the monitor has stopped.
+func (pm *PodMonitor) closeEvents() {
+	pm.subscribersMu.Lock()
+	defer pm.subscribersMu.Unlock()
+	if pm.subscribersClosed {
+		return
+	}
+	pm.subscribersClosed = true
+	for ch := range pm.subscribers {
+		close(ch)
+	}
+	pm.subscribers = nil
+	close(pm.eventsDone)
+}
+
+func (pm *PodMonitor) publish(event PodEvent) {
+	pm.subscribersMu.Lock()
+	defer pm.subscribersMu.Unlock()
+
+	for ch := range pm.subscribers {
+		select {
+		case ch <- event:
+		default:
+			dropped := pm.eventsDropped.Add(1)
+			pm.logger.Printf("⚠️  Events() channel full, dropping %s event for %s/%s (%d dropped so far)",
+				event.EventType, event.Namespace, event.PodName, dropped)
+		}
+	}
+}
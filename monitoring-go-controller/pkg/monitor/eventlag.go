@@ -0,0 +1,39 @@
+package monitor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// latestTransition returns the most recent LastTransitionTime among the
+// pod's conditions, zero when it has none.
+func latestTransition(pod *corev1.Pod) time.Time {
+	var latest time.Time
+	for _, condition := range pod.Status.Conditions {
+		if condition.LastTransitionTime.Time.After(latest) {
+			latest = condition.LastTransitionTime.Time
+		}
+	}
+	return latest
+}
+
+// observeLag sets event_lag_seconds from an event that carries a condition
+// transition newer than the tracked pod's. Other updates, such as a label
+// change, say nothing about how far behind the watch is. A transition stamped
+// ahead of our clock counts as no lag, and one beyond CLOCK_SKEW_TOLERANCE is
+// not reported at all.
+func (w *podWatcher) observeLag(pod *corev1.Pod, now time.Time) {
+	latest := latestTransition(pod)
+	if latest.IsZero() {
+		return
+	}
+	if oldPod, exists := w.trackedPod(pod.UID); exists && !latest.After(latestTransition(oldPod)) {
+		return
+	}
+	lag, ok := w.pm.elapsedSince(latest, now)
+	if !ok {
+		return
+	}
+	eventLagSeconds.Set(lag.Seconds())
+}
@@ -0,0 +1,135 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const eventWatchRetryInterval = 5 * time.Second
+
+// watchProbeEvents watches core/v1 Events for kubelet probe failures
+// (reason=Unhealthy) and surfaces them as PROBE_FAILED pod events. Probe
+// failures rarely show up in pod status, so the Events API is the only place
+// the "why is my pod not ready" answer lives. A repeated event is only
+// reported again when its count or last timestamp moves forward, so the relist
+// after a reconnect and updates that are not new occurrences stay quiet.
+func (pm *PodMonitor) watchProbeEvents(ctx context.Context, namespace string) {
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+		fields.OneTermEqualSelector("reason", "Unhealthy"),
+	)
+
+	seen := make(map[types.UID]kubeEventSeen)
+	pm.watchCoreEvents(ctx, namespace, "🩺 Watching probe failure events", selector, func(eventType watch.EventType, k8sEvent *corev1.Event) {
+		if eventType == watch.Deleted {
+			delete(seen, k8sEvent.UID)
+			return
+		}
+		if eventType != watch.Added && eventType != watch.Modified {
+			return
+		}
+		if !pm.namespaceInScope(k8sEvent.InvolvedObject.Namespace) {
+			return
+		}
+		count, lastSeen := kubeEventProgress(k8sEvent)
+		if previous, ok := seen[k8sEvent.UID]; ok && count <= previous.count && !lastSeen.After(previous.lastSeen) {
+			return
+		}
+		seen[k8sEvent.UID] = kubeEventSeen{count: count, lastSeen: lastSeen}
+		pm.recordProbeFailure(k8sEvent)
+
+		podEvent := PodEvent{
+			Timestamp: time.Now(),
+			EventType: "PROBE_FAILED",
+			PodName:   k8sEvent.InvolvedObject.Name,
+			Namespace: k8sEvent.InvolvedObject.Namespace,
+			Message:   "Probe failed",
+			Reason:    k8sEvent.Message,
+			ProbeType: probeType(k8sEvent.Message),
+		}
+		if pod, tracked := pm.trackedPod(k8sEvent.InvolvedObject.UID); tracked {
+			podEvent.Phase = string(pod.Status.Phase)
+			podEvent.Important = pm.isImportant(pod.Labels)
+			podEvent.ServiceAccount = pod.Spec.ServiceAccountName
+		}
+		pm.logEvent(podEvent)
+	})
+}
+
+// watchCoreEvents lists and then watches the core/v1 Events matching
+// selector, passing each one to handle, and re-establishes the watch until
+// ctx is done.
+func (pm *PodMonitor) watchCoreEvents(ctx context.Context, namespace, description string, selector fields.Selector, handle func(watch.EventType, *corev1.Event)) {
+	listOptions := metav1.ListOptions{FieldSelector: selector.String()}
+
+	for {
+		err := pm.watchCoreEventsOnce(ctx, namespace, description, listOptions, handle)
+		if ctx.Err() != nil {
+			return
+		}
+
+		pm.logger.Printf("⚠️  Event watch ended (%v), retrying in %v", err, eventWatchRetryInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventWatchRetryInterval):
+		}
+	}
+}
+
+func (pm *PodMonitor) watchCoreEventsOnce(ctx context.Context, namespace, description string, listOptions metav1.ListOptions, handle func(watch.EventType, *corev1.Event)) error {
+	// List first so the watch starts from the current resource version and
+	// does not replay events that happened before we started.
+	events, err := pm.clientset.CoreV1().Events(namespace).List(ctx, listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %v", err)
+	}
+
+	listOptions.ResourceVersion = events.ResourceVersion
+	watcher, err := pm.clientset.CoreV1().Events(namespace).Watch(ctx, listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to create event watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	label := namespace
+	if label == "" {
+		label = namespaceLabel(nil)
+	}
+	pm.logger.Printf("%s for namespace: %s", description, label)
+
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("event watch channel closed")
+			}
+
+			if k8sEvent, ok := event.Object.(*corev1.Event); ok {
+				handle(event.Type, k8sEvent)
+			}
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// probeType extracts the probe kind from a kubelet Unhealthy message such as
+// "Readiness probe failed: HTTP probe failed with statuscode: 500".
+func probeType(message string) string {
+	for _, probe := range []string{"Readiness", "Liveness", "Startup"} {
+		if strings.HasPrefix(message, probe+" probe") {
+			return strings.ToLower(probe)
+		}
+	}
+	return "unknown"
+}
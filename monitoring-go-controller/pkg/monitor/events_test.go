@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestProbeEventWatchReportsProbeFailures(t *testing.T) {
+	t.Setenv("WATCH_EVENTS", "true")
+	pod := testPod("default", "web")
+	pod.Status.Phase = corev1.PodRunning
+	pm, client := newTestMonitor(t, "default", pod)
+
+	eventWatchOpen := make(chan struct{})
+	var once sync.Once
+	client.PrependWatchReactor("events", func(k8stesting.Action) (bool, watch.Interface, error) {
+		once.Do(func() { close(eventWatchOpen) })
+		return false, nil, nil
+	})
+	events := startWatching(t, pm)
+	<-eventWatchOpen
+
+	tests := []struct {
+		name      string
+		message   string
+		probeType string
+	}{
+		{name: "readiness", message: "Readiness probe failed: HTTP probe failed with statuscode: 500", probeType: "readiness"},
+		{name: "liveness", message: "Liveness probe failed: dial tcp 10.0.0.7:8080: connect: connection refused", probeType: "liveness"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sEvent := &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: "web." + tt.name, Namespace: "default", UID: types.UID("web." + tt.name + "-uid")},
+				InvolvedObject: corev1.ObjectReference{
+					Kind:      "Pod",
+					Name:      "web",
+					Namespace: "default",
+					UID:       pod.UID,
+					FieldPath: "spec.containers{app}",
+				},
+				Reason:  "Unhealthy",
+				Message: tt.message,
+				Type:    corev1.EventTypeWarning,
+			}
+			if _, err := client.CoreV1().Events("default").Create(context.Background(), k8sEvent, metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			failed := nextEvent(t, events, "PROBE_FAILED")
+			if failed.PodName != "web" || failed.Reason != tt.message || failed.ProbeType != tt.probeType {
+				t.Errorf("PROBE_FAILED event = %+v, want %s probe failure of web", failed, tt.probeType)
+			}
+			if failed.Phase != "Running" {
+				t.Errorf("PROBE_FAILED phase = %q, want the tracked pod's phase", failed.Phase)
+			}
+		})
+	}
+}
+
+func TestProbeEventWatchReportsEachOccurrenceOnce(t *testing.T) {
+	t.Setenv("WATCH_EVENTS", "true")
+	pod := testPod("default", "web")
+	pm, client := newTestMonitor(t, "default", pod)
+
+	eventWatchOpen := make(chan struct{})
+	var once sync.Once
+	client.PrependWatchReactor("events", func(k8stesting.Action) (bool, watch.Interface, error) {
+		once.Do(func() { close(eventWatchOpen) })
+		return false, nil, nil
+	})
+	events := startWatching(t, pm)
+	<-eventWatchOpen
+
+	k8sEvent := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "web.unhealthy", Namespace: "default", UID: "web.unhealthy-uid"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      "web",
+			Namespace: "default",
+			UID:       pod.UID,
+			FieldPath: "spec.containers{app}",
+		},
+		Reason:  "Unhealthy",
+		Message: "Readiness probe failed: HTTP probe failed with statuscode: 500",
+		Type:    corev1.EventTypeWarning,
+		Count:   1,
+	}
+	ctx := context.Background()
+	if _, err := client.CoreV1().Events("default").Create(ctx, k8sEvent, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	nextEvent(t, events, "PROBE_FAILED")
+
+	// An update that is not a new occurrence must not be reported again;
+	// the next PROBE_FAILED has to be the one for the second occurrence.
+	k8sEvent.Annotations = map[string]string{"touched": "true"}
+	if _, err := client.CoreV1().Events("default").Update(ctx, k8sEvent, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	k8sEvent.Count = 2
+	k8sEvent.Message = "Readiness probe failed: HTTP probe failed with statuscode: 503"
+	if _, err := client.CoreV1().Events("default").Update(ctx, k8sEvent, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if failed := nextEvent(t, events, "PROBE_FAILED"); failed.Reason != k8sEvent.Message {
+		t.Errorf("PROBE_FAILED reason = %q, want the second occurrence %q", failed.Reason, k8sEvent.Message)
+	}
+}
@@ -0,0 +1,261 @@
+package monitor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// eventStoreMigrations are applied in order on startup. The number applied so
+// far is kept in SQLite's user_version pragma; append new steps, never edit
+// existing ones.
+var eventStoreMigrations = []string{
+	`CREATE TABLE events (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp  INTEGER NOT NULL,
+		namespace  TEXT NOT NULL,
+		pod_name   TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		event      TEXT NOT NULL
+	);
+	CREATE INDEX events_timestamp ON events (timestamp);
+	CREATE INDEX events_namespace_pod ON events (namespace, pod_name, timestamp);
+	CREATE INDEX events_event_type ON events (event_type, timestamp);`,
+}
+
+// eventStore persists events to a SQLite database so they can be queried
+// after the fact. Writes are batched in a transaction from its own goroutine;
+// events are dropped with an error when the buffer is full.
+type eventStore struct {
+	path   string
+	db     *sql.DB
+	logger *log.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	events chan PodEvent
+	done   chan struct{}
+}
+
+// EventQuery selects stored events. Empty fields match everything. Results
+// are newest first.
+type EventQuery struct {
+	Namespace string
+	PodName   string
+	EventType string
+	Since     time.Time
+	Limit     int
+	Offset    int
+}
+
+// openEventStore opens or creates the database at path and migrates it. It
+// returns nil when path is empty.
+func openEventStore(path string, logger *log.Logger) (*eventStore, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event database %s: %v", path, err)
+	}
+	// SQLite allows a single writer; one connection avoids "database is
+	// locked" errors between the writer and queries.
+	db.SetMaxOpenConns(1)
+
+	if err := migrateEventStore(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate event database %s: %v", path, err)
+	}
+
+	return &eventStore{
+		path:   path,
+		db:     db,
+		logger: logger,
+		events: make(chan PodEvent, 1000),
+		done:   make(chan struct{}),
+	}, nil
+}
+
+func migrateEventStore(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	for ; version < len(eventStoreMigrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(eventStoreMigrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %v", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Emit queues an event for the next write without blocking. The history
+// keeps the plain event even with --output-format=cloudevents, so /events
+// answers the same either way.
+func (s *eventStore) Emit(event PodEvent) error {
+	event.cloudEvent = nil
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("event database buffer full")
+	}
+}
+
+// run writes queued events until Close is called, grouping whatever is
+// queued into one transaction.
+func (s *eventStore) run() {
+	defer close(s.done)
+
+	for event := range s.events {
+		batch := []PodEvent{event}
+	drain:
+		for len(batch) < 100 {
+			select {
+			case next, ok := <-s.events:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, next)
+			default:
+				break drain
+			}
+		}
+
+		if err := s.write(batch); err != nil {
+			s.logger.Printf("❌ Failed to write %d events to %s: %v", len(batch), s.path, err)
+		}
+	}
+}
+
+// Close stops accepting events, waits up to timeout for pending writes and
+// closes the database.
+func (s *eventStore) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		s.db.Close()
+	case <-time.After(timeout):
+		s.logger.Println("⚠️  Timed out writing events to the event database")
+	}
+}
+
+func (s *eventStore) pending() int {
+	return len(s.events)
+}
+
+func (s *eventStore) write(batch []PodEvent) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO events (timestamp, namespace, pod_name, event_type, event) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, event := range batch {
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(event.Timestamp.UnixNano(), event.Namespace, event.PodName, event.EventType, string(eventJSON)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Query returns stored events matching q, newest first. A zero Limit
+// defaults to 100.
+func (s *eventStore) Query(ctx context.Context, q EventQuery) ([]PodEvent, error) {
+	var where []string
+	var args []any
+	if q.Namespace != "" {
+		where = append(where, "namespace = ?")
+		args = append(args, q.Namespace)
+	}
+	if q.PodName != "" {
+		where = append(where, "pod_name = ?")
+		args = append(args, q.PodName)
+	}
+	if q.EventType != "" {
+		where = append(where, "event_type = ?")
+		args = append(args, q.EventType)
+	}
+	if !q.Since.IsZero() {
+		where = append(where, "timestamp >= ?")
+		args = append(args, q.Since.UnixNano())
+	}
+
+	query := "SELECT event FROM events"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	query += " ORDER BY timestamp DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, q.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []PodEvent{}
+	for rows.Next() {
+		var eventJSON string
+		if err := rows.Scan(&eventJSON); err != nil {
+			return nil, err
+		}
+		var event PodEvent
+		if err := json.Unmarshal([]byte(eventJSON), &event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
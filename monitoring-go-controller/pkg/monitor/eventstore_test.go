@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+)
+
+func openMemoryEventStore(t *testing.T) *eventStore {
+	t.Helper()
+	store, err := openEventStore(":memory:", log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.db.Close() })
+	return store
+}
+
+func TestEventStoreMigrationsAreIdempotent(t *testing.T) {
+	store := openMemoryEventStore(t)
+
+	// A second run, as on the next startup, must find nothing to do.
+	if err := migrateEventStore(store.db); err != nil {
+		t.Fatalf("second migration: %v", err)
+	}
+
+	var version int
+	if err := store.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if version != len(eventStoreMigrations) {
+		t.Errorf("user_version = %d, want %d", version, len(eventStoreMigrations))
+	}
+	var indexes int
+	if err := store.db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = 'events' AND name LIKE 'events_%'`).Scan(&indexes); err != nil {
+		t.Fatal(err)
+	}
+	if indexes != 3 {
+		t.Errorf("events has %d indexes, want 3", indexes)
+	}
+}
+
+func TestEventStoreQuery(t *testing.T) {
+	store := openMemoryEventStore(t)
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Oldest first; Query returns them newest first.
+	events := []PodEvent{
+		{Timestamp: base, EventType: "ADDED", Namespace: "shop", PodName: "web", Message: "1"},
+		{Timestamp: base.Add(time.Minute), EventType: "MODIFIED", Namespace: "shop", PodName: "web", Message: "2"},
+		{Timestamp: base.Add(2 * time.Minute), EventType: "ADDED", Namespace: "shop", PodName: "api", Message: "3"},
+		{Timestamp: base.Add(3 * time.Minute), EventType: "DELETED", Namespace: "shop", PodName: "web", Message: "4"},
+		{Timestamp: base.Add(4 * time.Minute), EventType: "ADDED", Namespace: "billing", PodName: "web", Message: "5"},
+	}
+	if err := store.write(events); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query EventQuery
+		want  []string
+	}{
+		{name: "all", query: EventQuery{}, want: []string{"5", "4", "3", "2", "1"}},
+		{name: "namespace", query: EventQuery{Namespace: "shop"}, want: []string{"4", "3", "2", "1"}},
+		{name: "pod", query: EventQuery{Namespace: "shop", PodName: "web"}, want: []string{"4", "2", "1"}},
+		{name: "event type", query: EventQuery{EventType: "ADDED"}, want: []string{"5", "3", "1"}},
+		{name: "since", query: EventQuery{Since: base.Add(2 * time.Minute)}, want: []string{"5", "4", "3"}},
+		{name: "limit", query: EventQuery{Limit: 2}, want: []string{"5", "4"}},
+		{name: "limit and offset", query: EventQuery{Limit: 2, Offset: 2}, want: []string{"3", "2"}},
+		{name: "offset past the end", query: EventQuery{Offset: 5}, want: []string{}},
+		{name: "no match", query: EventQuery{Namespace: "shop", PodName: "db"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Query(context.Background(), tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			messages := []string{}
+			for _, event := range got {
+				messages = append(messages, event.Message)
+			}
+			if len(messages) != len(tt.want) {
+				t.Fatalf("Query(%+v) = %v, want %v", tt.query, messages, tt.want)
+			}
+			for i := range messages {
+				if messages[i] != tt.want[i] {
+					t.Fatalf("Query(%+v) = %v, want %v", tt.query, messages, tt.want)
+				}
+			}
+		})
+	}
+
+	got, err := store.Query(context.Background(), EventQuery{PodName: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].EventType != "ADDED" || got[0].Namespace != "shop" || !got[0].Timestamp.Equal(events[2].Timestamp) {
+		t.Errorf("round trip of the api event = %+v, want %+v", got, events[2])
+	}
+}
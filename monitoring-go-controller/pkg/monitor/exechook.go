@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// execHook runs a local command for every emitted event, piping the event
+// JSON to its stdin. Commands run in the background with a concurrency limit
+// and a timeout so a slow or hanging command never blocks the watch loop;
+// events arriving while every slot is busy are skipped.
+type execHook struct {
+	args    []string
+	timeout time.Duration
+	slots   chan struct{}
+	logger  *log.Logger
+
+	failures atomic.Int64
+	timeouts atomic.Int64
+	skipped  atomic.Int64
+}
+
+// newExecHookFromEnv builds the hook from EXEC_ON_EVENT, EXEC_CONCURRENCY and
+// EXEC_TIMEOUT. It returns nil when EXEC_ON_EVENT is unset.
+func newExecHookFromEnv(logger *log.Logger) *execHook {
+	args := strings.Fields(os.Getenv("EXEC_ON_EVENT"))
+	if len(args) == 0 {
+		return nil
+	}
+
+	concurrency := envInt("EXEC_CONCURRENCY", 4)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &execHook{
+		args:    args,
+		timeout: envDuration("EXEC_TIMEOUT", 10*time.Second),
+		slots:   make(chan struct{}, concurrency),
+		logger:  logger,
+	}
+}
+
+// Emit runs the hook for one event without blocking. The event is skipped
+// when EXEC_CONCURRENCY commands are already running.
+func (h *execHook) Emit(event PodEvent) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event to JSON: %v", err)
+	}
+	return h.run(eventJSON)
+}
+
+func (h *execHook) run(eventJSON []byte) error {
+	select {
+	case h.slots <- struct{}{}:
+	default:
+		skipped := h.skipped.Add(1)
+		return fmt.Errorf("exec hook busy (%d skipped so far)", skipped)
+	}
+
+	go func() {
+		defer func() { <-h.slots }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, h.args[0], h.args[1:]...)
+		cmd.Stdin = bytes.NewReader(eventJSON)
+		output, err := cmd.CombinedOutput()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			timeouts := h.timeouts.Add(1)
+			h.logger.Printf("⏱️  Exec hook %s timed out after %v (%d timeouts so far)", h.args[0], h.timeout, timeouts)
+			return
+		}
+		if err != nil {
+			failures := h.failures.Add(1)
+			h.logger.Printf("❌ Exec hook %s failed: %v: %s (%d failures so far)",
+				h.args[0], err, strings.TrimSpace(string(output)), failures)
+		}
+	}()
+	return nil
+}
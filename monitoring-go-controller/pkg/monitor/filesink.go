@@ -0,0 +1,193 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileSink appends each event as one JSON line to a file. When the file
+// would grow past maxSize it is rotated: path becomes path.1, path.1 becomes
+// path.2 and so on, keeping maxBackups old files. Lines are buffered and
+// flushed whenever the queue runs empty and on shutdown.
+type fileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	logger     *log.Logger
+
+	file   *os.File
+	writer *bufio.Writer
+	size   int64
+
+	mu     sync.RWMutex
+	closed bool
+	events chan PodEvent
+	done   chan struct{}
+}
+
+// newFileSink opens path for appending. It returns nil when path is empty.
+// maxSizeMB of zero disables rotation.
+func newFileSink(path string, maxSizeMB, maxBackups int, logger *log.Logger) (*fileSink, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+	if maxSizeMB < 0 || maxBackups < 0 {
+		return nil, fmt.Errorf("output file max size and backups must not be negative, got %d and %d", maxSizeMB, maxBackups)
+	}
+
+	s := &fileSink{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+		logger:     logger,
+		events:     make(chan PodEvent, 1000),
+		done:       make(chan struct{}),
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open output file %s: %v", s.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat output file %s: %v", s.path, err)
+	}
+
+	s.file = file
+	s.writer = bufio.NewWriter(file)
+	s.size = info.Size()
+	return nil
+}
+
+// Emit queues an event for writing without blocking.
+func (s *fileSink) Emit(event PodEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("output file buffer full")
+	}
+}
+
+// run writes queued events until Close is called, flushing whenever the
+// queue is drained.
+func (s *fileSink) run() {
+	defer close(s.done)
+
+	for event := range s.events {
+		s.write(event)
+		if len(s.events) == 0 {
+			s.flush()
+		}
+	}
+	s.flush()
+	if err := s.file.Close(); err != nil {
+		s.logger.Printf("⚠️  Failed to close output file %s: %v", s.path, err)
+	}
+}
+
+// Close stops accepting events and waits up to timeout for buffered lines
+// to be written.
+func (s *fileSink) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		s.logger.Printf("⚠️  Timed out writing events to %s", s.path)
+	}
+}
+
+func (s *fileSink) pending() int {
+	return len(s.events)
+}
+
+func (s *fileSink) write(event PodEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Printf("❌ Failed to marshal event for %s: %v", s.path, err)
+		return
+	}
+	line = append(line, '\n')
+
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			s.logger.Printf("❌ Failed to rotate %s: %v", s.path, err)
+		}
+	}
+
+	n, err := s.writer.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		s.logger.Printf("❌ Failed to write event to %s: %v", s.path, err)
+	}
+}
+
+func (s *fileSink) flush() {
+	if err := s.writer.Flush(); err != nil {
+		s.logger.Printf("❌ Failed to flush %s: %v", s.path, err)
+	}
+}
+
+// rotate closes the current file, shifts the backups and opens a new file.
+// With no backups retained the current file is simply truncated.
+func (s *fileSink) rotate() error {
+	s.flush()
+	if err := s.file.Close(); err != nil {
+		s.logger.Printf("⚠️  Failed to close output file %s: %v", s.path, err)
+	}
+
+	if s.maxBackups == 0 {
+		if err := os.Truncate(s.path, 0); err != nil {
+			return err
+		}
+		return s.open()
+	}
+
+	os.Remove(s.backupPath(s.maxBackups))
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(s.backupPath(i), s.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, s.backupPath(1)); err != nil {
+		// Keep writing to the current file rather than losing events.
+		if openErr := s.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	return s.open()
+}
+
+func (s *fileSink) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", s.path, n)
+}
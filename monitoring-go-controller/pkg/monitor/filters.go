@@ -0,0 +1,194 @@
+package monitor
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podChangeTypes are the event types --event-types selects from.
+var podChangeTypes = map[string]bool{"ADDED": true, "MODIFIED": true, "DELETED": true}
+
+// parseEventTypes parses a comma-separated subset of podChangeTypes. It
+// returns nil, meaning every type, when spec is empty.
+func parseEventTypes(spec string) (map[string]bool, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	eventTypes := make(map[string]bool)
+	for _, eventType := range strings.Split(spec, ",") {
+		eventType = strings.ToUpper(strings.TrimSpace(eventType))
+		if eventType == "" {
+			continue
+		}
+		if !podChangeTypes[eventType] {
+			return nil, fmt.Errorf("invalid event type %q: must be ADDED, MODIFIED or DELETED", eventType)
+		}
+		eventTypes[eventType] = true
+	}
+	return eventTypes, nil
+}
+
+// compilePodRegex compiles a pod name pattern from the named flag. It
+// returns nil when pattern is empty.
+func compilePodRegex(flag, pattern string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s %q: %v", flag, pattern, err)
+	}
+	return re, nil
+}
+
+// parseMinSeverity maps --min-severity to the log level events are compared
+// against with eventLevel.
+func parseMinSeverity(severity string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case severityWarning:
+		return slog.LevelWarn, nil
+	case severityCritical:
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid minimum severity %q: must be info, warning or critical", severity)
+}
+
+// eventFilters are the settings that drop events after the watch; the pods
+// are still tracked. They are replaced as a whole on a config reload.
+type eventFilters struct {
+	// eventTypes, when non-nil, limits emitted ADDED/MODIFIED/DELETED events
+	// to these types; minSeverity drops pod events below this level.
+	eventTypes  map[string]bool
+	minSeverity slog.Level
+	// excludeNamespaces and the pod name regexes drop events by pod.
+	excludeNamespaces map[string]bool
+	excludePodRegex   *regexp.Regexp
+	includePodRegex   *regexp.Regexp
+}
+
+func newEventFilters(cfg Config) (*eventFilters, error) {
+	eventTypes, err := parseEventTypes(cfg.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	minSeverity, err := parseMinSeverity(cfg.MinSeverity)
+	if err != nil {
+		return nil, err
+	}
+
+	excludeNamespaces, err := normalizeNamespaces(strings.Split(cfg.ExcludeNamespaces, ","))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --exclude-namespaces: %v", err)
+	}
+
+	excludePodRegex, err := compilePodRegex("exclude-pod-regex", cfg.ExcludePodRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	includePodRegex, err := compilePodRegex("include-pod-regex", cfg.IncludePodRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &eventFilters{
+		eventTypes:        eventTypes,
+		minSeverity:       minSeverity,
+		excludeNamespaces: make(map[string]bool, len(excludeNamespaces)),
+		excludePodRegex:   excludePodRegex,
+		includePodRegex:   includePodRegex,
+	}
+	for _, namespace := range excludeNamespaces {
+		f.excludeNamespaces[namespace] = true
+	}
+	return f, nil
+}
+
+// Watch strategies select where the namespace scope is applied. server_side
+// asks the API server for the namespace only; client_side watches every
+// namespace and discards out-of-scope pods in-process.
+const (
+	watchStrategyServerSide = "server_side"
+	watchStrategyClientSide = "client_side"
+)
+
+// inScope reports whether a pod returned by the watch belongs to one of the
+// monitored namespaces and, with --owner-kind, to the selected workload.
+// Only the client_side strategy returns pods from other namespaces.
+func (pm *PodMonitor) inScope(pod *corev1.Pod) bool {
+	return pm.namespaceInScope(pod.Namespace) && pm.ownedPod(pod)
+}
+
+func (pm *PodMonitor) namespaceInScope(namespace string) bool {
+	namespaces := pm.watchedNamespaces()
+	if len(namespaces) == 0 {
+		return true
+	}
+	for _, monitored := range namespaces {
+		if namespace == monitored {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressed reports whether the configured filters drop an event before it
+// is emitted. Events for important pods are never dropped.
+func (pm *PodMonitor) suppressed(event PodEvent) bool {
+	if event.Important {
+		return false
+	}
+
+	if pm.podExcluded(event) {
+		return true
+	}
+	if event.PodName == "" {
+		return false
+	}
+
+	filters := pm.filters.Load()
+	if filters.eventTypes != nil && podChangeTypes[event.EventType] && !filters.eventTypes[event.EventType] {
+		return true
+	}
+
+	if eventLevel(event) < filters.minSeverity {
+		return true
+	}
+
+	return false
+}
+
+// podExcluded reports whether the namespace, service account or pod name
+// filters exclude the pod an event is about, whatever the kind of event.
+func (pm *PodMonitor) podExcluded(event PodEvent) bool {
+	filters := pm.filters.Load()
+	if filters.excludeNamespaces[event.Namespace] {
+		return true
+	}
+
+	// Aggregate events (pod counts and the like) carry no pod name and are
+	// not subject to per-pod filters.
+	if event.PodName == "" {
+		return false
+	}
+
+	if pm.serviceAccountFilter != "" && event.ServiceAccount != pm.serviceAccountFilter {
+		return true
+	}
+
+	if filters.excludePodRegex != nil && filters.excludePodRegex.MatchString(event.PodName) {
+		return true
+	}
+	if filters.includePodRegex != nil && !filters.includePodRegex.MatchString(event.PodName) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceAccountFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		event  PodEvent
+		want   bool
+	}{
+		{name: "matching service account", filter: "auditor",
+			event: PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop", ServiceAccount: "auditor"}},
+		{name: "other service account", filter: "auditor",
+			event: PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop", ServiceAccount: "default"}, want: true},
+		{name: "no service account", filter: "auditor",
+			event: PodEvent{EventType: "DELETED", PodName: "web", Namespace: "shop"}, want: true},
+		{name: "aggregate event", filter: "auditor",
+			event: PodEvent{EventType: "NS_POD_COUNTS", Namespace: "shop"}},
+		{name: "important pod", filter: "auditor",
+			event: PodEvent{EventType: "MODIFIED", PodName: "web", Namespace: "shop", ServiceAccount: "default", Important: true}},
+		{name: "no filter", event: PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop", ServiceAccount: "default"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SERVICE_ACCOUNT_FILTER", tt.filter)
+			pm, _ := newTestMonitor(t, "shop")
+			if got := pm.suppressed(tt.event); got != tt.want {
+				t.Errorf("suppressed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceAccountFilterTracksEveryPod(t *testing.T) {
+	t.Setenv("SERVICE_ACCOUNT_FILTER", "auditor")
+	pm, client := newTestMonitor(t, "default")
+	events := startWatching(t, pm)
+	pods := client.CoreV1().Pods("default")
+
+	other := testPod("default", "other")
+	other.Spec.ServiceAccountName = "default"
+	audited := testPod("default", "web")
+	audited.Spec.ServiceAccountName = "auditor"
+	for _, pod := range []*corev1.Pod{other, audited} {
+		if _, err := pods.Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	added := nextEvent(t, events, "ADDED")
+	if added.PodName != "web" || added.ServiceAccount != "auditor" {
+		t.Errorf("ADDED event = %+v, want web reported with service account auditor", added)
+	}
+	expectNoEvent(t, events, "ADDED", 200*time.Millisecond)
+	if _, tracked := pm.trackedPod("other-uid"); !tracked {
+		t.Error("the filtered pod is not tracked")
+	}
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// flapDetection turns a burst of container restarts into a single
+// POD_FLAPPING event. A restarts threshold of 0 disables it.
+type flapDetection struct {
+	restarts int
+	window   time.Duration
+	cooldown time.Duration
+}
+
+// flapState is the per-pod restart history, kept under podWatcher.mu.
+type flapState struct {
+	restarts      []time.Time
+	cooldownUntil time.Time
+}
+
+// recordRestarts notes the restarts between two versions of a pod. It returns
+// a POD_FLAPPING event when the pod has crossed the threshold, and reports
+// whether the pod is in its post-flap cooldown, during which its restarts are
+// not reported individually.
+func (w *podWatcher) recordRestarts(oldPod, pod *corev1.Pod, now time.Time) (*PodEvent, bool) {
+	pm := w.pm
+	if pm.flap.restarts <= 0 {
+		return nil, false
+	}
+
+	restarts := restartCount(pod) - restartCount(oldPod)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	uid := string(pod.UID)
+	state := w.flaps[uid]
+	if state == nil {
+		if restarts <= 0 {
+			return nil, false
+		}
+		state = &flapState{}
+		w.flaps[uid] = state
+	}
+
+	for i := int32(0); i < restarts; i++ {
+		state.restarts = append(state.restarts, now)
+	}
+	cutoff := now.Add(-pm.flap.window)
+	for len(state.restarts) > 0 && state.restarts[0].Before(cutoff) {
+		state.restarts = state.restarts[1:]
+	}
+
+	if now.Before(state.cooldownUntil) {
+		return nil, true
+	}
+	if len(state.restarts) < pm.flap.restarts {
+		return nil, false
+	}
+
+	state.cooldownUntil = now.Add(pm.flap.cooldown)
+	event := pm.newPodEvent("POD_FLAPPING", pod)
+	event.Message = "Pod flapping"
+	event.Reason = fmt.Sprintf("Pod flapping: %d restarts in %v", len(state.restarts), pm.flap.window)
+	event.Severity = severityWarning
+	state.restarts = nil
+	return &event, false
+}
+
+// withoutRestarts returns a copy of pod whose containers keep the restart
+// counts and CrashLoopBackOff states they had in oldPod, so that
+// getChangeReason reports only the changes a restart does not explain, such
+// as readiness, OOMKilled, termination or image changes.
+func withoutRestarts(oldPod, pod *corev1.Pod) *corev1.Pod {
+	masked := pod.DeepCopy()
+	maskRestarts(oldPod.Status.ContainerStatuses, masked.Status.ContainerStatuses)
+	maskRestarts(oldPod.Status.InitContainerStatuses, masked.Status.InitContainerStatuses)
+	maskRestarts(oldPod.Status.EphemeralContainerStatuses, masked.Status.EphemeralContainerStatuses)
+	return masked
+}
+
+func maskRestarts(oldStatuses, statuses []corev1.ContainerStatus) {
+	oldByName := make(map[string]corev1.ContainerStatus, len(oldStatuses))
+	for _, status := range oldStatuses {
+		oldByName[status.Name] = status
+	}
+	for i := range statuses {
+		old, existed := oldByName[statuses[i].Name]
+		if !existed {
+			continue
+		}
+		statuses[i].RestartCount = old.RestartCount
+		if waitingReason(statuses[i]) == "CrashLoopBackOff" || waitingReason(old) == "CrashLoopBackOff" {
+			statuses[i].State.Waiting = old.State.Waiting
+		}
+	}
+}
+
+func restartCount(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
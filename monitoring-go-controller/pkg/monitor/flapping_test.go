@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFlapDetectionIsOffByDefault(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("FLAP_RESTARTS", "")
+	cfg, err := DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Flap.restarts != 0 {
+		t.Errorf("default flap restarts = %d, want 0 (disabled)", cfg.Flap.restarts)
+	}
+}
+
+func TestFlapCooldownSuppressesOnlyRestarts(t *testing.T) {
+	t.Setenv("FLAP_RESTARTS", "2")
+	t.Setenv("FLAP_COOLDOWN", "1h")
+	pod := testPod("default", "web")
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", Image: "nginx:1.25", Ready: true}}
+	pm, client := newTestMonitor(t, "default", pod)
+	events := startWatching(t, pm)
+	pods := client.CoreV1().Pods("default")
+
+	update := func(change func(status *corev1.ContainerStatus)) {
+		t.Helper()
+		change(&pod.Status.ContainerStatuses[0])
+		if _, err := pods.Update(context.Background(), pod, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	restart := func(status *corev1.ContainerStatus) { status.RestartCount++ }
+
+	update(restart)
+	nextEvent(t, events, "MODIFIED")
+	update(restart)
+	if flapping := nextEvent(t, events, "POD_FLAPPING"); !strings.Contains(flapping.Reason, "2 restarts") {
+		t.Errorf("POD_FLAPPING reason = %q", flapping.Reason)
+	}
+	nextEvent(t, events, "MODIFIED")
+
+	// Restarts alone stay quiet during the cooldown.
+	update(func(status *corev1.ContainerStatus) {
+		status.RestartCount++
+		status.State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+	})
+	expectNoEvent(t, events, "MODIFIED", 200*time.Millisecond)
+
+	tests := []struct {
+		name   string
+		change func(status *corev1.ContainerStatus)
+		want   string
+	}{
+		{
+			name: "readiness loss",
+			change: func(status *corev1.ContainerStatus) {
+				status.RestartCount++
+				status.Ready = false
+			},
+			want: "Container app readiness changed to false",
+		},
+		{
+			name: "OOMKilled",
+			change: func(status *corev1.ContainerStatus) {
+				status.RestartCount++
+				status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{
+					Reason:     "OOMKilled",
+					ExitCode:   137,
+					FinishedAt: metav1.Now(),
+				}
+			},
+			want: "Container app OOMKilled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update(tt.change)
+			modified := nextEvent(t, events, "MODIFIED")
+			if !strings.Contains(modified.Reason, tt.want) {
+				t.Errorf("MODIFIED reason = %q, want it to contain %q", modified.Reason, tt.want)
+			}
+			if strings.Contains(modified.Reason, "restart count") {
+				t.Errorf("MODIFIED reason = %q still reports the restart", modified.Reason)
+			}
+		})
+	}
+
+	pod.Spec.Containers[0].Image = "nginx:1.26"
+	update(restart)
+	if modified := nextEvent(t, events, "MODIFIED"); !strings.Contains(modified.Reason, "image changed") {
+		t.Errorf("MODIFIED reason = %q, want the image change", modified.Reason)
+	}
+}
@@ -0,0 +1,119 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// batchingSink is implemented by asynchronous sinks that hold a batch in
+// their goroutine between deliveries, so an empty queue does not mean the
+// events were delivered.
+type batchingSink interface {
+	// flush delivers the queued events and the current batch at once and
+	// returns how many events that was.
+	flush(ctx context.Context) (int, error)
+}
+
+// requestFlush asks a batching sink's run loop to deliver its batch and waits
+// until it has, the sink has been closed, or ctx is done. The run loop
+// answers with the number of events delivered.
+func requestFlush(ctx context.Context, flushes chan<- chan int, done <-chan struct{}) (int, error) {
+	ack := make(chan int, 1)
+	select {
+	case flushes <- ack:
+	case <-done:
+		return 0, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	select {
+	case delivered := <-ack:
+		return delivered, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// flushQueued delivers what the asynchronous sinks have queued without
+// closing them, waiting until ctx is done. It returns how many queued events
+// were delivered and how many are still queued.
+func (pm *PodMonitor) flushQueued(ctx context.Context) (flushed, pending int) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, sink := range pm.asyncSinks {
+		wg.Add(1)
+		go func(sink asyncSink) {
+			defer wg.Done()
+			delivered := flushSink(ctx, sink)
+			mu.Lock()
+			defer mu.Unlock()
+			flushed += delivered
+			pending += sink.pending()
+		}(sink)
+	}
+	wg.Wait()
+	return flushed, pending
+}
+
+// flushSink waits for one sink to deliver what it has queued and returns how
+// many events that was.
+func flushSink(ctx context.Context, sink asyncSink) int {
+	if batching, ok := sink.(batchingSink); ok {
+		delivered, _ := batching.flush(ctx)
+		return delivered
+	}
+
+	// The other sinks deliver each event as they take it from the queue.
+	queued := sink.pending()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for sink.pending() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if delivered := queued - sink.pending(); delivered > 0 {
+				return delivered
+			}
+			return 0
+		}
+	}
+	return queued
+}
+
+// flushResult is the POST /flush response.
+type flushResult struct {
+	Flushed int `json:"flushed"`
+	Pending int `json:"pending"`
+}
+
+// handleFlush delivers the events queued for the asynchronous sinks, waiting
+// up to --shutdown-timeout. It answers 504 when events are still queued.
+func (pm *PodMonitor) handleFlush(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !pm.authorized(r) {
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), pm.shutdownTimeout)
+	defer cancel()
+	flushed, pending := pm.flushQueued(ctx)
+	if pending > 0 {
+		pm.logger.Printf("⚠️  Flushed %d queued events to sinks, %d still queued after %v", flushed, pending, pm.shutdownTimeout)
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if pending > 0 {
+		rw.WriteHeader(http.StatusGatewayTimeout)
+	}
+	json.NewEncoder(rw).Encode(flushResult{Flushed: flushed, Pending: pending})
+}
@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// postFlush calls handleFlush on pm and decodes the response.
+func postFlush(t *testing.T, pm *PodMonitor, method, token string) (int, flushResult) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(pm.handleFlush))
+	defer server.Close()
+
+	req, err := http.NewRequest(method, server.URL+"/flush", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var result flushResult
+	if resp.Header.Get("Content-Type") == "application/json" {
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode, result
+}
+
+func TestHandleFlushDeliversBatchedEvents(t *testing.T) {
+	webhook := newHookServer(t)
+	loki := newHookServer(t)
+	t.Setenv("WEBHOOK_URL", webhook.URL)
+	t.Setenv("LOKI_URL", loki.URL)
+	// Without a flush, Loki would hold the batch for an hour.
+	t.Setenv("LOKI_BATCH_WAIT", "1h")
+	pm, _ := newTestMonitor(t, "default")
+	for _, sink := range pm.asyncSinks {
+		go sink.run()
+	}
+	t.Cleanup(func() { pm.flushSinks(5 * time.Second) })
+
+	for _, name := range []string{"web", "api", "worker"} {
+		pm.logEvent(PodEvent{EventType: "ADDED", PodName: name, Namespace: "default"})
+	}
+
+	status, result := postFlush(t, pm, http.MethodPost, "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	// The webhook may have delivered some events before the flush; the
+	// Loki batch is only delivered by it.
+	if result.Flushed < 3 || result.Flushed > 6 || result.Pending != 0 {
+		t.Errorf("flush result = %+v, want 3 to 6 flushed and none pending", result)
+	}
+	if got := len(loki.received()); got != 1 {
+		t.Errorf("Loki received %d pushes by the time the flush returned, want 1", got)
+	}
+	// The webhook may still be posting the last event it took off its queue.
+	waitFor(t, "the webhook to receive every event", func() bool {
+		return len(webhook.received()) == 3
+	})
+}
+
+func TestHandleFlushTimesOut(t *testing.T) {
+	t.Setenv("WEBHOOK_URL", "http://127.0.0.1:1")
+	t.Setenv("SHUTDOWN_TIMEOUT", "50ms")
+	pm, _ := newTestMonitor(t, "default")
+	// The sink is not running, so nothing drains its queue.
+	for i := 0; i < 2; i++ {
+		pm.logEvent(PodEvent{EventType: "ADDED", PodName: "web", Namespace: "default"})
+	}
+
+	status, result := postFlush(t, pm, http.MethodPost, "")
+	if status != http.StatusGatewayTimeout || result.Flushed != 0 || result.Pending != 2 {
+		t.Errorf("flush = %d %+v, want 504 with 2 events pending", status, result)
+	}
+}
+
+func TestHandleFlushRejectsBadRequests(t *testing.T) {
+	t.Setenv("WEBHOOK_URL", "http://127.0.0.1:1")
+	t.Setenv("API_TOKEN", "s3cret")
+	pm, _ := newTestMonitor(t, "default")
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   int
+	}{
+		{name: "GET", method: http.MethodGet, token: "s3cret", want: http.StatusMethodNotAllowed},
+		{name: "no token", method: http.MethodPost, want: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodPost, token: "guess", want: http.StatusUnauthorized},
+		{name: "valid token", method: http.MethodPost, token: "s3cret", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := postFlush(t, pm, tt.method, tt.token); status != tt.want {
+				t.Errorf("status = %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// handleHealthz reports liveness: 200 once the monitor has reached the
+// Kubernetes API.
+func (pm *PodMonitor) handleHealthz(rw http.ResponseWriter, _ *http.Request) {
+	if !pm.connected.Load() {
+		http.Error(rw, "not connected to the Kubernetes API", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(rw, "ok")
+}
+
+// handleReadyz reports readiness: 200 only while every pod watcher has
+// completed its initial list and has an open watch. A watcher that is backing
+// off before reconnecting, or has given up, makes the monitor not ready. A
+// standby replica waiting for the leader lease is ready.
+func (pm *PodMonitor) handleReadyz(rw http.ResponseWriter, _ *http.Request) {
+	if pm.leaderElection != nil && !pm.leading.Load() {
+		fmt.Fprintln(rw, "ok (standby)")
+		return
+	}
+	for _, w := range pm.podWatchers() {
+		if !w.ready.Load() {
+			http.Error(rw, fmt.Sprintf("watch for namespace %s is not active", w.label()), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	fmt.Fprintln(rw, "ok")
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// restartingCountKey is reported next to the pod phases in HEALTH_SUMMARY
+// events: the number of pods with a container in CrashLoopBackOff.
+const restartingCountKey = "Restarting"
+
+// reportHealthSummary periodically emits one HEALTH_SUMMARY event covering
+// every tracked pod. Besides giving a health snapshot it doubles as a
+// heartbeat showing the monitor is alive.
+func (pm *PodMonitor) reportHealthSummary(ctx context.Context) {
+	ticker := time.NewTicker(pm.summaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			pm.logEvent(pm.healthSummary())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendSinkHeartbeats sends a HEALTH_SUMMARY to the connection-based sinks
+// every sinkHeartbeatInterval, so intermediaries do not drop their idle
+// connections and a dead connection fails on the next beat instead of on
+// the next real event. It runs whether or not SUMMARY_INTERVAL is set.
+func (pm *PodMonitor) sendSinkHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(pm.sinkHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			pm.emitSinkHeartbeat()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// emitSinkHeartbeat sends one HEALTH_SUMMARY through the sinks. It skips
+// stdout, the exec hook, the local file and database, and the Slack and
+// Teams channels people read; none of them keeps a connection open.
+func (pm *PodMonitor) emitSinkHeartbeat() {
+	event := pm.healthSummary()
+	event.SchemaVersion = pm.schemaVersion
+	event.Timestamp = event.Timestamp.In(pm.timestamps.location)
+	event.timeFormat = pm.timestamps.format
+	if pm.cloudEvents {
+		event.cloudEvent = newCloudEventContext(event, pm.clusterName)
+	}
+
+	for _, sink := range pm.sinks {
+		switch sink.(type) {
+		case *execHook, *fileSink, *eventStore, *slackSink, *teamsSink:
+			continue
+		}
+		if sink == pm.logSink {
+			continue
+		}
+		if err := sink.Emit(event); err != nil {
+			pm.logger.Printf("⚠️  Dropping sink heartbeat: %v", err)
+		}
+	}
+}
+
+type podRestarts struct {
+	name     string
+	restarts int32
+}
+
+func (pm *PodMonitor) healthSummary() PodEvent {
+	counts := make(map[string]int)
+	var top []podRestarts
+	total := 0
+
+	for _, w := range pm.podWatchers() {
+		w.mu.RLock()
+		for _, pod := range w.existingPods {
+			total++
+			counts[string(pod.Status.Phase)]++
+			if inCrashLoop(pod) {
+				counts[restartingCountKey]++
+			}
+			if restarts := restartCount(pod); restarts > 0 {
+				top = append(top, podRestarts{name: pod.Namespace + "/" + pod.Name, restarts: restarts})
+			}
+		}
+		w.mu.RUnlock()
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].restarts != top[j].restarts {
+			return top[i].restarts > top[j].restarts
+		}
+		return top[i].name < top[j].name
+	})
+	if limit := max(pm.summaryTopN, 0); len(top) > limit {
+		top = top[:limit]
+	}
+
+	event := PodEvent{
+		Timestamp: time.Now(),
+		EventType: "HEALTH_SUMMARY",
+		Namespace: namespaceLabel(pm.watchedNamespaces()),
+		Counts:    counts,
+		Message:   fmt.Sprintf("%d pods tracked, %d restarting", total, counts[restartingCountKey]),
+	}
+	if len(top) > 0 {
+		parts := make([]string, 0, len(top))
+		for _, pod := range top {
+			parts = append(parts, fmt.Sprintf("%s=%d", pod.name, pod.restarts))
+		}
+		event.Reason = "Most restarts: " + strings.Join(parts, ", ")
+	}
+	return event
+}
@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSinkHeartbeatSkipsStdout(t *testing.T) {
+	t.Setenv("SINK_HEARTBEAT_INTERVAL", "20ms")
+	t.Setenv("SUMMARY_INTERVAL", "")
+	pm, _ := newTestMonitor(t, "default", testPod("default", "web"))
+	stdout := &recordingSink{}
+	pm.logSink = stdout
+	pm.AddSink(stdout)
+	sink := &recordingSink{}
+	pm.AddSink(sink)
+	events := startWatching(t, pm)
+
+	waitFor(t, "a heartbeat on the sink", func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.events) > 0
+	})
+	sink.mu.Lock()
+	heartbeat := sink.events[0]
+	sink.mu.Unlock()
+	if heartbeat.EventType != "HEALTH_SUMMARY" || heartbeat.Message != "1 pods tracked, 0 restarting" {
+		t.Errorf("heartbeat = %+v, want a HEALTH_SUMMARY of the tracked pod", heartbeat)
+	}
+
+	// Neither stdout nor Events() subscribers see heartbeats.
+	expectNoEvent(t, events, "HEALTH_SUMMARY", 100*time.Millisecond)
+	stdout.mu.Lock()
+	defer stdout.mu.Unlock()
+	if len(stdout.events) > 0 {
+		t.Errorf("stdout got %d events, want no heartbeats", len(stdout.events))
+	}
+}
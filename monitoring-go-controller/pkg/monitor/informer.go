@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	watchModeWatch    = "watch"
+	watchModeInformer = "informer"
+)
+
+// runInformer is the informer-mode alternative to watchPods. client-go's
+// shared informer handles listing, resuming and relisting; its callbacks feed
+// the same handlePodEvent path as the raw watch, so the output is identical.
+func (w *podWatcher) runInformer(ctx context.Context) error {
+	pm := w.pm
+
+	factory := informers.NewSharedInformerFactoryWithOptions(pm.clientset, 0,
+		informers.WithNamespace(w.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = pm.fieldSelector
+		}))
+	informer := factory.Core().V1().Pods().Informer()
+	w.mu.Lock()
+	w.informerVersion = informer.LastSyncResourceVersion
+	w.mu.Unlock()
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok || !pm.inScope(pod) {
+				return
+			}
+			// Pods present at startup are tracked silently, as with the
+			// initial List of the raw watch.
+			if isInInitialList {
+				w.trackPod(pod)
+				return
+			}
+			w.handlePodEvent(watch.Added, pod)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, okOld := oldObj.(*corev1.Pod)
+			pod, ok := newObj.(*corev1.Pod)
+			if !okOld || !ok || !pm.inScope(pod) {
+				return
+			}
+			// Relists deliver unchanged objects as updates.
+			if oldPod.ResourceVersion == pod.ResourceVersion {
+				return
+			}
+			w.handlePodEvent(watch.Modified, pod)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			pod, ok := obj.(*corev1.Pod)
+			if !ok || !pm.inScope(pod) {
+				return
+			}
+			w.handlePodEvent(watch.Deleted, pod)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	factory.Start(stop)
+
+	synced := make(chan bool, 1)
+	go func() {
+		synced <- cache.WaitForCacheSync(stop, informer.HasSynced)
+	}()
+
+	select {
+	case ok := <-synced:
+		if ok {
+			w.untrackMissing(informer.GetStore())
+			w.ready.Store(true)
+			defer w.ready.Store(false)
+			pm.logger.Printf("🚀 Starting pod monitor for namespace: %s (found %d existing pods, informer mode)", w.label(), len(informer.GetStore().ListKeys()))
+		}
+	case <-ctx.Done():
+	case <-pm.stopCh:
+	}
+
+	select {
+	case <-ctx.Done():
+		pm.logger.Println("🛑 Context cancelled, stopping pod monitor")
+		return ctx.Err()
+	case <-pm.stopCh:
+		pm.logger.Println("🛑 Stop signal received, stopping pod monitor")
+		return nil
+	}
+}
+
+// untrackMissing drops the tracked pods the informer did not list, such as
+// pods a watcher kept across a reload that are no longer in scope.
+func (w *podWatcher) untrackMissing(store cache.Store) {
+	// Pods added after the snapshot are not candidates.
+	w.mu.RLock()
+	tracked := make([]string, 0, len(w.existingPods))
+	for uid := range w.existingPods {
+		tracked = append(tracked, uid)
+	}
+	w.mu.RUnlock()
+
+	listed := make(map[string]bool)
+	for _, obj := range store.List() {
+		if pod, ok := obj.(*corev1.Pod); ok && w.pm.inScope(pod) {
+			listed[string(pod.UID)] = true
+		}
+	}
+	for _, uid := range tracked {
+		if !listed[uid] {
+			w.untrackPod(types.UID(uid))
+		}
+	}
+}
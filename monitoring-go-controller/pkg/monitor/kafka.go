@@ -0,0 +1,186 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	kafkaBatchSize    = 100
+	kafkaBatchWait    = time.Second
+	kafkaWriteTimeout = 10 * time.Second
+)
+
+// kafkaSink produces each event as a JSON message keyed by namespace/pod_name,
+// so that with the hash balancer all events for a pod land on the same
+// partition in order. Events are batched from its own goroutine; producer
+// errors are logged and the batch is dropped.
+type kafkaSink struct {
+	topic  string
+	writer *kafka.Writer
+	logger *log.Logger
+
+	mu      sync.RWMutex
+	closed  bool
+	events  chan PodEvent
+	flushes chan chan int
+	done    chan struct{}
+}
+
+// newKafkaSink builds a sink producing to topic on the comma-separated
+// brokers. It returns nil when brokers is empty.
+func newKafkaSink(brokers, topic string, logger *log.Logger) (*kafkaSink, error) {
+	var addrs []string
+	for _, broker := range strings.Split(brokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			addrs = append(addrs, broker)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return nil, errors.New("a Kafka topic is required when Kafka brokers are set")
+	}
+
+	return &kafkaSink{
+		topic: topic,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(addrs...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireOne,
+			BatchSize:    kafkaBatchSize,
+			// Batches are assembled in run; don't wait for more.
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: kafkaWriteTimeout,
+		},
+		logger:  logger,
+		events:  make(chan PodEvent, 10*kafkaBatchSize),
+		flushes: make(chan chan int),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Emit queues an event for the next batch without blocking.
+func (s *kafkaSink) Emit(event PodEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("Kafka buffer full")
+	}
+}
+
+// run produces queued events until Close is called, sending a batch when it
+// is full, when kafkaBatchWait has passed or on a flush.
+func (s *kafkaSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(kafkaBatchWait)
+	defer ticker.Stop()
+
+	batch := make([]kafka.Message, 0, kafkaBatchSize)
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				s.produce(batch)
+				return
+			}
+			batch = s.add(batch, event)
+		case ack := <-s.flushes:
+			// Only what is queued now; later events wait for the next batch.
+			queued := len(s.events)
+			delivered := len(batch) + queued
+			for i := 0; i < queued; i++ {
+				batch = s.add(batch, <-s.events)
+			}
+			s.produce(batch)
+			batch = batch[:0]
+			ack <- delivered
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.produce(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// add appends event to batch, producing the batch once it is full.
+func (s *kafkaSink) add(batch []kafka.Message, event PodEvent) []kafka.Message {
+	value, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Printf("❌ Failed to marshal event for Kafka: %v", err)
+		return batch
+	}
+	batch = append(batch, kafka.Message{
+		Key:   []byte(event.Namespace + "/" + event.PodName),
+		Value: value,
+		Time:  event.Timestamp,
+	})
+	if len(batch) >= kafkaBatchSize {
+		s.produce(batch)
+		batch = batch[:0]
+	}
+	return batch
+}
+
+func (s *kafkaSink) flush(ctx context.Context) (int, error) {
+	return requestFlush(ctx, s.flushes, s.done)
+}
+
+// Close stops accepting events, waits up to timeout for the final batch and
+// closes the producer.
+func (s *kafkaSink) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		s.logger.Println("⚠️  Timed out flushing events to Kafka")
+	}
+	if err := s.writer.Close(); err != nil {
+		s.logger.Printf("⚠️  Failed to close Kafka producer: %v", err)
+	}
+}
+
+func (s *kafkaSink) pending() int {
+	return len(s.events)
+}
+
+func (s *kafkaSink) produce(batch []kafka.Message) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaWriteTimeout)
+	defer cancel()
+
+	if err := s.writer.WriteMessages(ctx, batch...); err != nil {
+		s.logger.Printf("❌ Failed to produce %d events to Kafka topic %s: %v", len(batch), s.topic, err)
+	}
+}
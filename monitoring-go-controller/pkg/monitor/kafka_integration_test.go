@@ -0,0 +1,147 @@
+//go:build integration
+
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const kafkaTestPartitions = 3
+
+// TestKafkaSinkKeysEventsByPod needs a Kafka broker that allows topic
+// creation, e.g. `docker run -p 9092:9092 apache/kafka`, at
+// KAFKA_TEST_BROKERS:
+//
+//	KAFKA_TEST_BROKERS=localhost:9092 go test -tags integration -run Kafka ./pkg/monitor
+func TestKafkaSinkKeysEventsByPod(t *testing.T) {
+	brokers := os.Getenv("KAFKA_TEST_BROKERS")
+	if brokers == "" {
+		t.Skip("KAFKA_TEST_BROKERS not set")
+	}
+	broker := strings.TrimSpace(strings.Split(brokers, ",")[0])
+	topic := "pod-monitor-test-" + time.Now().Format("150405000000")
+	createKafkaTopic(t, broker, topic)
+
+	sink, err := newKafkaSink(brokers, topic, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go sink.run()
+
+	pods := []string{"web", "api", "worker"}
+	phases := []string{"Pending", "Running", "Succeeded"}
+	for _, phase := range phases {
+		for _, pod := range pods {
+			event := PodEvent{Timestamp: time.Now(), EventType: "MODIFIED", PodName: pod, Namespace: "default", Phase: phase}
+			if err := sink.Emit(event); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// Close flushes the last batch before returning.
+	sink.Close(30 * time.Second)
+
+	partitionOf := make(map[string]int)
+	seen := make(map[string][]string)
+	for partition := 0; partition < kafkaTestPartitions; partition++ {
+		for _, msg := range readKafkaPartition(t, broker, topic, partition) {
+			var event PodEvent
+			if err := json.Unmarshal(msg.Value, &event); err != nil {
+				t.Fatalf("message is not a PodEvent: %v\n%s", err, msg.Value)
+			}
+			key := string(msg.Key)
+			if want := event.Namespace + "/" + event.PodName; key != want {
+				t.Errorf("message key = %q, want %q", key, want)
+			}
+			if p, ok := partitionOf[key]; ok && p != partition {
+				t.Errorf("events for %s landed on partitions %d and %d", key, p, partition)
+			}
+			partitionOf[key] = partition
+			seen[key] = append(seen[key], event.Phase)
+		}
+	}
+
+	for _, pod := range pods {
+		key := "default/" + pod
+		if strings.Join(seen[key], ",") != strings.Join(phases, ",") {
+			t.Errorf("events for %s arrived as %v, want %v in order", key, seen[key], phases)
+		}
+	}
+}
+
+// createKafkaTopic creates topic through the cluster controller and deletes
+// it when the test ends.
+func createKafkaTopic(t *testing.T, broker, topic string) {
+	t.Helper()
+	conn, err := kafka.Dial("tcp", broker)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close()
+	controller, err := conn.Controller()
+	if err != nil {
+		t.Fatal(err)
+	}
+	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = controllerConn.CreateTopics(kafka.TopicConfig{Topic: topic, NumPartitions: kafkaTestPartitions, ReplicationFactor: 1})
+	if err != nil {
+		controllerConn.Close()
+		t.Fatalf("create topic: %v", err)
+	}
+	t.Cleanup(func() {
+		controllerConn.DeleteTopics(topic)
+		controllerConn.Close()
+	})
+}
+
+// readKafkaPartition returns every message in one partition of topic.
+func readKafkaPartition(t *testing.T, broker, topic string, partition int) []kafka.Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := kafka.DialLeader(ctx, "tcp", broker, topic, partition)
+	if err != nil {
+		t.Fatalf("connect to partition %d: %v", partition, err)
+	}
+	defer conn.Close()
+
+	last, err := conn.ReadLastOffset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Seek(0, kafka.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	batch := conn.ReadBatch(1, 10e6)
+	defer batch.Close()
+
+	var messages []kafka.Message
+	for int64(len(messages)) < last {
+		msg, err := batch.ReadMessage()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read partition %d: %v", partition, err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
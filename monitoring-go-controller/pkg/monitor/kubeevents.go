@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// KubeEvent is the latest core/v1 Event recorded for a pod, such as a
+// FailedScheduling, BackOff or Unhealthy event, carrying the scheduler's or
+// kubelet's explanation for a change.
+type KubeEvent struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// kubeEventSeen is how far a core/v1 Event had progressed when last handled.
+type kubeEventSeen struct {
+	count    int32
+	lastSeen time.Time
+}
+
+// correlateKubeEvents watches core/v1 Events about pods in the watcher's
+// namespace and keeps the latest one per tracked pod, to be attached to that
+// pod's next MODIFIED or DELETED event. A repeated event is only recorded
+// again when its count or last timestamp moves forward.
+func (w *podWatcher) correlateKubeEvents(ctx context.Context) {
+	seen := make(map[types.UID]kubeEventSeen)
+	selector := fields.OneTermEqualSelector("involvedObject.kind", "Pod")
+
+	w.pm.watchCoreEvents(ctx, w.namespace, "🔗 Correlating Kubernetes events", selector, func(eventType watch.EventType, k8sEvent *corev1.Event) {
+		if eventType == watch.Deleted {
+			delete(seen, k8sEvent.UID)
+			return
+		}
+		if eventType != watch.Added && eventType != watch.Modified {
+			return
+		}
+
+		count, lastSeen := kubeEventProgress(k8sEvent)
+		if previous, ok := seen[k8sEvent.UID]; ok && count <= previous.count && !lastSeen.After(previous.lastSeen) {
+			return
+		}
+		seen[k8sEvent.UID] = kubeEventSeen{count: count, lastSeen: lastSeen}
+		if k8sEvent.Reason == "Unhealthy" {
+			w.pm.recordProbeFailure(k8sEvent)
+		}
+
+		w.recordKubeEvent(k8sEvent.InvolvedObject.UID, &KubeEvent{
+			Type:     k8sEvent.Type,
+			Reason:   k8sEvent.Reason,
+			Message:  k8sEvent.Message,
+			Count:    count,
+			LastSeen: lastSeen,
+		})
+	})
+}
+
+// kubeEventProgress returns an event's occurrence count and last occurrence
+// time, falling back to the event series fields newer components report.
+func kubeEventProgress(k8sEvent *corev1.Event) (int32, time.Time) {
+	count, lastSeen := k8sEvent.Count, k8sEvent.LastTimestamp.Time
+	if k8sEvent.Series != nil {
+		count, lastSeen = k8sEvent.Series.Count, k8sEvent.Series.LastObservedTime.Time
+	}
+	if lastSeen.IsZero() {
+		lastSeen = k8sEvent.EventTime.Time
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count, lastSeen
+}
+
+// recordKubeEvent keeps a Kubernetes event for a tracked pod; events about
+// pods we do not track are ignored.
+func (w *podWatcher) recordKubeEvent(uid types.UID, event *KubeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, tracked := w.existingPods[string(uid)]; tracked {
+		w.kubeEvents[string(uid)] = event
+	}
+}
+
+// takeKubeEvent returns the Kubernetes event recorded for a pod since its
+// last emitted event, if any.
+func (w *podWatcher) takeKubeEvent(uid types.UID) *KubeEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	event := w.kubeEvents[string(uid)]
+	delete(w.kubeEvents, string(uid))
+	return event
+}
@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// startupLatency returns how long a pod took from creation to its first
+// Ready, when this update is the one that made it Ready. Both timestamps come
+// from the API server, so our clock is not involved.
+func startupLatency(oldPod, pod *corev1.Pod) (time.Duration, bool) {
+	if podReady(oldPod) != nil {
+		return 0, false
+	}
+	ready := podReady(pod)
+	if ready == nil || ready.LastTransitionTime.IsZero() || pod.CreationTimestamp.IsZero() {
+		return 0, false
+	}
+
+	latency := ready.LastTransitionTime.Sub(pod.CreationTimestamp.Time)
+	if latency < 0 {
+		return 0, false
+	}
+	return latency, true
+}
+
+// podReady returns the pod's Ready condition if it is True.
+func podReady(pod *corev1.Pod) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		condition := &pod.Status.Conditions[i]
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// firstReady records that a pod has become Ready and reports whether this is
+// the first time, so a pod that goes unready and back is not counted again.
+func (w *podWatcher) firstReady(uid string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.startupReported[uid] {
+		return false
+	}
+	w.startupReported[uid] = true
+	return true
+}
+
+// podLifetime returns how long a deleted pod existed. Deleted objects can be
+// stale tombstones without a creation timestamp, which are skipped.
+func (pm *PodMonitor) podLifetime(pod *corev1.Pod, now time.Time) (time.Duration, bool) {
+	if pod.CreationTimestamp.IsZero() {
+		return 0, false
+	}
+	return pm.elapsedSince(pod.CreationTimestamp.Time, now)
+}
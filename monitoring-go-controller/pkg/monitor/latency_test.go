@@ -0,0 +1,119 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStartupLatency(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	condition := func(status corev1.ConditionStatus, at time.Time) []corev1.PodCondition {
+		return []corev1.PodCondition{{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(at)}}
+	}
+
+	tests := []struct {
+		name     string
+		old, new []corev1.PodCondition
+		want     time.Duration
+		wantOK   bool
+	}{
+		{name: "becomes Ready", new: condition(corev1.ConditionTrue, created.Add(12*time.Second)), want: 12 * time.Second, wantOK: true},
+		{name: "Ready after not Ready", old: condition(corev1.ConditionFalse, created), new: condition(corev1.ConditionTrue, created.Add(90*time.Second)),
+			want: 90 * time.Second, wantOK: true},
+		{name: "already Ready", old: condition(corev1.ConditionTrue, created.Add(time.Second)), new: condition(corev1.ConditionTrue, created.Add(time.Second))},
+		{name: "not Ready", new: condition(corev1.ConditionFalse, created.Add(time.Second))},
+		{name: "no transition time", new: condition(corev1.ConditionTrue, time.Time{})},
+		{name: "Ready before creation", new: condition(corev1.ConditionTrue, created.Add(-time.Second))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldPod, newPod := testPod("default", "web"), testPod("default", "web")
+			oldPod.CreationTimestamp = metav1.NewTime(created)
+			newPod.CreationTimestamp = metav1.NewTime(created)
+			oldPod.Status.Conditions = tt.old
+			newPod.Status.Conditions = tt.new
+
+			got, ok := startupLatency(oldPod, newPod)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("startupLatency = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func startupObservations(t *testing.T) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := podStartupSeconds.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestStartupLatencyIsReportedOnce(t *testing.T) {
+	pod := testPod("default", "web")
+	pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	pm, client := newTestMonitor(t, "default", pod)
+	events := startWatching(t, pm)
+	pods := client.CoreV1().Pods("default")
+	before := startupObservations(t)
+
+	setReady := func(status corev1.ConditionStatus, at time.Time) PodEvent {
+		t.Helper()
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(at)}}
+		if _, err := pods.Update(context.Background(), pod, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		return nextEvent(t, events, "MODIFIED")
+	}
+
+	ready := setReady(corev1.ConditionTrue, pod.CreationTimestamp.Add(15*time.Second))
+	if ready.StartupSeconds != 15 {
+		t.Errorf("startup_seconds on first Ready = %v, want 15", ready.StartupSeconds)
+	}
+	setReady(corev1.ConditionFalse, pod.CreationTimestamp.Add(30*time.Second))
+	if again := setReady(corev1.ConditionTrue, pod.CreationTimestamp.Add(45*time.Second)); again.StartupSeconds != 0 {
+		t.Errorf("startup_seconds on a later Ready = %v, want it omitted", again.StartupSeconds)
+	}
+	if got := startupObservations(t) - before; got != 1 {
+		t.Errorf("pod_startup_seconds observed %d times, want 1", got)
+	}
+}
+
+func TestObserveLagClampsFutureTransitions(t *testing.T) {
+	pm, _ := newTestMonitor(t, "default")
+	pm.clockSkewTolerance = time.Minute
+	w := newPodWatcher(pm, "default")
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	lag := func() float64 {
+		t.Helper()
+		var m dto.Metric
+		if err := eventLagSeconds.Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetGauge().GetValue()
+	}
+	transitioned := func(name string, at time.Time) *corev1.Pod {
+		pod := testPod("default", name)
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(at)}}
+		return pod
+	}
+
+	w.observeLag(transitioned("behind", now.Add(-3*time.Second)), now)
+	if got := lag(); got != 3 {
+		t.Fatalf("event_lag_seconds = %v, want 3", got)
+	}
+	w.observeLag(transitioned("skewed", now.Add(5*time.Second)), now)
+	if got := lag(); got != 0 {
+		t.Errorf("event_lag_seconds for a transition ahead of our clock = %v, want 0", got)
+	}
+	w.observeLag(transitioned("bogus", now.Add(time.Hour)), now)
+	if got := lag(); got != 0 {
+		t.Errorf("event_lag_seconds for a transition beyond the skew tolerance = %v, want it left at 0", got)
+	}
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// serviceAccountNamespaceFile holds the namespace the controller runs in when
+// it runs in-cluster.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// leaderElection identifies the Lease replicas compete for and this replica's
+// identity in it.
+type leaderElection struct {
+	lease     string
+	namespace string
+	identity  string
+}
+
+func newLeaderElection(lease, namespace string) (*leaderElection, error) {
+	lease = strings.TrimSpace(lease)
+	if lease == "" {
+		return nil, errors.New("leader election lease name must not be empty")
+	}
+
+	namespace = strings.TrimSpace(namespace)
+	if namespace == "" {
+		namespace = ownNamespace()
+	}
+
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine leader election identity: %v", err)
+	}
+
+	return &leaderElection{lease: lease, namespace: namespace, identity: identity}, nil
+}
+
+// ownNamespace returns the namespace of the controller's service account,
+// or "default" outside a cluster.
+func ownNamespace() string {
+	if namespace, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if trimmed := strings.TrimSpace(string(namespace)); trimmed != "" {
+			return trimmed
+		}
+	}
+	return "default"
+}
+
+// runAsLeader blocks until this replica acquires the lease, then calls run
+// with a context that is cancelled if the lease is lost. It returns run's
+// error, or an error when leadership was lost before shutdown, so the
+// replica restarts as a standby instead of carrying on with stale state.
+func (pm *PodMonitor) runAsLeader(ctx context.Context, run func(context.Context) error) error {
+	le := pm.leaderElection
+
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var runErr error
+	var finished bool
+	started := make(chan struct{})
+	runDone := make(chan struct{})
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Name: le.lease, Namespace: le.namespace},
+			Client:     pm.clientset.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: le.identity},
+		},
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+		ReleaseOnCancel: true,
+		Name:            le.lease,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				close(started)
+				defer close(runDone)
+				pm.leading.Store(true)
+				pm.logger.Printf("👑 Acquired leader lease %s/%s as %s", le.namespace, le.lease, le.identity)
+
+				runErr = run(leaderCtx)
+				// Release the lease when the watchers stop on their own.
+				finished = leaderCtx.Err() == nil
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				pm.leading.Store(false)
+				pm.logger.Printf("👑 Released leader lease %s/%s", le.namespace, le.lease)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != le.identity {
+					pm.logger.Printf("👑 Standing by: %s holds leader lease %s/%s", identity, le.namespace, le.lease)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up leader election: %v", err)
+	}
+
+	pm.logger.Printf("👑 Waiting for leader lease %s/%s as %s", le.namespace, le.lease, le.identity)
+	elector.Run(electionCtx)
+
+	select {
+	case <-started:
+	default:
+		// Never became leader; we are shutting down.
+		return nil
+	}
+	<-runDone
+
+	switch {
+	case runErr != nil:
+		return runErr
+	case finished || ctx.Err() != nil:
+		return nil
+	default:
+		return errors.New("lost leader lease")
+	}
+}
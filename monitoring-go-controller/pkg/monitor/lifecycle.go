@@ -0,0 +1,163 @@
+package monitor
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// version is stamped at build time with -ldflags "-X pod-monitor/pkg/monitor.version=...".
+var version = "dev"
+
+// emitLifecycleEvent emits MONITOR_STARTED/MONITOR_STOPPED through the normal
+// event pipeline so consumers can tell when this instance's coverage began
+// and ended.
+func (pm *PodMonitor) emitLifecycleEvent(eventType, message string) {
+	pm.logEvent(PodEvent{
+		Timestamp: time.Now(),
+		EventType: eventType,
+		Namespace: strings.Join(pm.watchedNamespaces(), ","),
+		Message:   message,
+		Config:    pm.effectiveConfig(),
+	})
+}
+
+// effectiveConfig summarizes the running configuration. Values that may carry
+// credentials (URLs, command arguments) are redacted.
+func (pm *PodMonitor) effectiveConfig() map[string]string {
+	config := map[string]string{
+		"version":          version,
+		"schema_version":   strconv.Itoa(pm.schemaVersion),
+		"namespace":        namespaceLabel(pm.watchedNamespaces()),
+		"watch_strategy":   pm.watchStrategy,
+		"watch_mode":       pm.watchMode,
+		"watch_events":     strconv.FormatBool(pm.watchEvents),
+		"watch_nodes":      strconv.FormatBool(pm.nodeWatcher != nil),
+		"correlate_events": strconv.FormatBool(pm.correlateEvents),
+	}
+	if len(pm.pvcWatchers) > 0 {
+		config["pvc_pending_threshold"] = pm.pvcPendingThreshold.String()
+	}
+	for _, w := range pm.workloadWatchers {
+		config["watch_"+strings.ToLower(w.kind.name)+"s"] = "true"
+	}
+	if pm.clusterName != "" {
+		config["cluster"] = pm.clusterName
+	}
+	if pm.importantLabelKey != "" {
+		config["important_label"] = pm.importantLabelKey
+		if pm.importantLabelValue != "" {
+			config["important_label"] += "=" + pm.importantLabelValue
+		}
+	}
+	if pm.leaderElection != nil {
+		config["leader_election_lease"] = pm.leaderElection.namespace + "/" + pm.leaderElection.lease
+	}
+	if pm.serviceAccountFilter != "" {
+		config["service_account_filter"] = pm.serviceAccountFilter
+	}
+	if pm.cloudEvents {
+		config["output_format"] = outputFormatCloudEvents
+	}
+	if pm.impersonate != "" {
+		config["impersonate"] = pm.impersonate
+	}
+	if pm.strictRBAC {
+		config["strict_rbac"] = "true"
+	}
+	if pm.retryForever {
+		config["on_watch_failure"] = watchFailureRetryForever
+	}
+	if pm.podCountInterval > 0 {
+		config["pod_count_interval"] = pm.podCountInterval.String()
+	}
+	if pm.resyncPeriod > 0 {
+		config["resync_period"] = pm.resyncPeriod.String()
+	}
+	if pm.summaryInterval > 0 {
+		config["summary_interval"] = pm.summaryInterval.String()
+	}
+	if pm.terminalLingerThreshold > 0 {
+		config["terminal_linger_threshold"] = pm.terminalLingerThreshold.String()
+	}
+	if pm.pendingThreshold > 0 {
+		config["pending_threshold"] = pm.pendingThreshold.String()
+	}
+	if pm.terminatingSlack > 0 {
+		config["terminating_slack"] = pm.terminatingSlack.String()
+	}
+	if pm.clockSkewTolerance > 0 {
+		config["clock_skew_tolerance"] = pm.clockSkewTolerance.String()
+	}
+	if pm.otel != nil {
+		config["otel_endpoint"] = redactURL(pm.otel.endpoint)
+	}
+	if pm.metricsClient != nil {
+		config["usage_interval"] = pm.usageInterval.String()
+	}
+	if pm.nodeLabels != nil {
+		config["node_label_refresh"] = pm.nodeLabels.interval.String()
+	}
+	if pm.services != nil {
+		config["service_refresh"] = pm.services.interval.String()
+	}
+
+	var sinks []string
+	if pm.logEvents {
+		sinks = append(sinks, "stdout")
+	}
+	if pm.loki != nil {
+		sinks = append(sinks, "loki")
+		config["loki_url"] = redactURL(pm.loki.url)
+	}
+	if pm.webhook != nil {
+		sinks = append(sinks, "webhook")
+		config["webhook_url"] = redactURL(pm.webhook.url)
+	}
+	if pm.slack != nil {
+		sinks = append(sinks, "slack")
+	}
+	if pm.teams != nil {
+		sinks = append(sinks, "teams")
+	}
+	if pm.file != nil {
+		sinks = append(sinks, "file")
+		config["output_file"] = pm.file.path
+	}
+	if pm.kafka != nil {
+		sinks = append(sinks, "kafka")
+		config["kafka_topic"] = pm.kafka.topic
+	}
+	if pm.redis != nil {
+		sinks = append(sinks, "redis")
+		config["redis_channel"] = pm.redis.channel
+	}
+	if pm.nats != nil {
+		sinks = append(sinks, "nats")
+		config["nats_subject"] = pm.nats.subject
+	}
+	if pm.store != nil {
+		sinks = append(sinks, "sqlite")
+		config["db_path"] = pm.store.path
+	}
+	if pm.execHook != nil {
+		sinks = append(sinks, "exec")
+		config["exec_on_event"] = pm.execHook.args[0]
+	}
+	config["sinks"] = strings.Join(sinks, ",")
+
+	return config
+}
+
+// redactURL drops user info and query parameters, which commonly carry
+// credentials.
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "<redacted>"
+	}
+	parsed.User = nil
+	parsed.RawQuery = ""
+	return parsed.String()
+}
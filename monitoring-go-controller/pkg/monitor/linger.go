@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// watchTerminalLinger periodically scans tracked pods for ones that finished
+// (Succeeded/Failed) but were never cleaned up, which usually points at a
+// garbage-collection or controller problem.
+func (pm *PodMonitor) watchTerminalLinger(ctx context.Context) {
+	ticker := time.NewTicker(lingerScanInterval(pm.terminalLingerThreshold))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, w := range pm.podWatchers() {
+				for _, event := range w.findLingeringPods(time.Now()) {
+					pm.logEvent(event)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// findLingeringPods returns one TERMINAL_LINGER event for each pod that has
+// crossed the threshold since the last scan. Each pod is reported only once.
+func (w *podWatcher) findLingeringPods(now time.Time) []PodEvent {
+	pm := w.pm
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []PodEvent
+	for uid, pod := range w.existingPods {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		if w.lingerReported[uid] {
+			continue
+		}
+
+		lingering, ok := pm.elapsedSince(w.phaseSince[uid], now)
+		if !ok || lingering < pm.terminalLingerThreshold {
+			continue
+		}
+
+		w.lingerReported[uid] = true
+		event := pm.newPodEvent("TERMINAL_LINGER", pod)
+		event.Message = "Terminated pod not deleted"
+		event.Reason = fmt.Sprintf("Pod in %s phase for %v without being deleted",
+			pod.Status.Phase, lingering.Round(time.Second))
+		event.PhaseDurationSeconds = lingering.Seconds()
+		events = append(events, event)
+	}
+	return events
+}
+
+// lingerScanInterval scans a few times per threshold, bounded to keep short
+// thresholds responsive and long ones cheap.
+func lingerScanInterval(threshold time.Duration) time.Duration {
+	interval := threshold / 4
+	if interval < 10*time.Second {
+		return 10 * time.Second
+	}
+	if interval > time.Minute {
+		return time.Minute
+	}
+	return interval
+}
@@ -0,0 +1,217 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	logFormatJSON = "json"
+	logFormatText = "text"
+)
+
+// newLogging builds the operational logger and the stdout event sink. By
+// default both go through one slog handler, so every line is a structured
+// record filtered by level. With legacy set, the original "[POD-MONITOR]"
+// logger and the emoji LogSink are used instead; its line prefix can only
+// be written in local time or UTC, so other time zones and formats only
+// apply to the JSON event line.
+//
+// Records and events at WARN and above go to errOut, the rest to out; pass
+// the same writer for both to keep a single stream. With table set, events
+// are written as table rows instead, whatever the log format. color colors
+// the legacy emoji lines and the table rows. level is a LevelVar so a config
+// reload can change it while the monitor runs.
+func newLogging(out, errOut io.Writer, format string, level *slog.LevelVar, legacy bool, ts timestamps, color, table bool) (*log.Logger, EventSink, error) {
+	logger, eventSink, err := newLoggers(out, errOut, format, level, legacy, ts, color)
+	if err != nil {
+		return nil, nil, err
+	}
+	if table {
+		eventSink = &tableSink{out: out, errOut: errOut, color: color}
+	}
+	return logger, eventSink, nil
+}
+
+func newLoggers(out, errOut io.Writer, format string, level *slog.LevelVar, legacy bool, ts timestamps, color bool) (*log.Logger, EventSink, error) {
+	if legacy {
+		flags := log.LstdFlags | log.Lmicroseconds
+		if ts.location == time.UTC {
+			flags |= log.LUTC
+		}
+		logger := log.New(out, "[POD-MONITOR] ", flags)
+		events := &LogSink{logger: logger, color: color}
+		if errOut == out {
+			return logger, events, nil
+		}
+		warnings := &LogSink{logger: log.New(errOut, "[POD-MONITOR] ", flags), color: color}
+		return logger, &splitSink{info: events, warn: warnings}, nil
+	}
+
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: ts.replaceTimeAttr}
+	newHandler := func(w io.Writer) slog.Handler {
+		if strings.ToLower(format) == logFormatText {
+			return slog.NewTextHandler(w, options)
+		}
+		return slog.NewJSONHandler(w, options)
+	}
+	switch strings.ToLower(format) {
+	case logFormatJSON, logFormatText:
+	default:
+		return nil, nil, fmt.Errorf("invalid log format %q: must be %s or %s", format, logFormatJSON, logFormatText)
+	}
+
+	handler := newHandler(out)
+	if errOut != out {
+		handler = levelSplitHandler{info: handler, warn: newHandler(errOut)}
+	}
+
+	return slog.NewLogLogger(handler, slog.LevelInfo), NewSlogSink(slog.New(handler)), nil
+}
+
+// parseLogLevel parses --log-level.
+func parseLogLevel(level string) (slog.Level, error) {
+	var slogLevel slog.Level
+	if err := slogLevel.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", level)
+	}
+	return slogLevel, nil
+}
+
+// levelSplitHandler sends records at WARN and above to warn and the rest to
+// info.
+type levelSplitHandler struct {
+	info, warn slog.Handler
+}
+
+func (h levelSplitHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= slog.LevelWarn {
+		return h.warn.Enabled(ctx, level)
+	}
+	return h.info.Enabled(ctx, level)
+}
+
+func (h levelSplitHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelWarn {
+		return h.warn.Handle(ctx, record)
+	}
+	return h.info.Handle(ctx, record)
+}
+
+func (h levelSplitHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelSplitHandler{info: h.info.WithAttrs(attrs), warn: h.warn.WithAttrs(attrs)}
+}
+
+func (h levelSplitHandler) WithGroup(name string) slog.Handler {
+	return levelSplitHandler{info: h.info.WithGroup(name), warn: h.warn.WithGroup(name)}
+}
+
+// splitSink sends events logged at WARN and above (see eventLevel) to warn
+// and the rest to info.
+type splitSink struct {
+	info, warn EventSink
+}
+
+func (s *splitSink) Emit(event PodEvent) error {
+	if eventLevel(event) >= slog.LevelWarn {
+		return s.warn.Emit(event)
+	}
+	return s.info.Emit(event)
+}
+
+// SlogSink writes each event as a single structured log record.
+type SlogSink struct {
+	logger *slog.Logger
+}
+
+// NewSlogSink returns a SlogSink writing to logger.
+func NewSlogSink(logger *slog.Logger) *SlogSink {
+	return &SlogSink{logger: logger}
+}
+
+// Emit logs the event with the event message as the record message and each
+// field of the event JSON, the same document the other sinks get, as an
+// attribute in field order. Fields named like the record's own time, level
+// and msg keys, such as the CloudEvents "time", get an "event_" prefix.
+func (s *SlogSink) Emit(event PodEvent) error {
+	attrs, err := eventAttrs(event)
+	if err != nil {
+		return err
+	}
+	s.logger.LogAttrs(context.Background(), eventLevel(event), event.Message, attrs...)
+	return nil
+}
+
+// eventAttrs turns the top-level fields of the event JSON into attributes.
+// Strings, numbers and booleans become plain values; objects and arrays are
+// kept as JSON.
+func eventAttrs(event PodEvent) ([]slog.Attr, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var attrs []slog.Attr
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		switch key {
+		case slog.TimeKey, slog.LevelKey, slog.MessageKey:
+			key = "event_" + key
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, slog.Any(key, jsonValue(raw)))
+	}
+	return attrs, nil
+}
+
+func jsonValue(raw json.RawMessage) any {
+	switch raw[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return s
+		}
+	case 't', 'f':
+		return raw[0] == 't'
+	case 'n':
+		return nil
+	case '{', '[':
+		return raw
+	}
+	return json.Number(raw)
+}
+
+// eventLevel logs events that usually need attention at WARN: anything with a
+// warning severity, probe failures, lingering or stuck pods and restarts.
+func eventLevel(event PodEvent) slog.Level {
+	switch {
+	case event.Severity == severityCritical:
+		return slog.LevelError
+	case event.Severity == severityWarning:
+		return slog.LevelWarn
+	case event.EventType == "PROBE_FAILED" || event.EventType == "TERMINAL_LINGER" || event.EventType == "POD_PENDING" || event.EventType == "POD_FLAPPING":
+		return slog.LevelWarn
+	case strings.Contains(event.Reason, "restart count changed"):
+		return slog.LevelWarn
+	}
+	return slog.LevelInfo
+}
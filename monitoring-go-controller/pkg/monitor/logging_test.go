@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlogSinkRecordCarriesTheEventJSON(t *testing.T) {
+	priority := int32(1000)
+	event := PodEvent{
+		SchemaVersion:     schemaVersion1,
+		Timestamp:         time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		EventType:         "MODIFIED",
+		PodName:           "web",
+		Namespace:         "shop",
+		PodIP:             "10.0.0.7",
+		NodeName:          "node-1",
+		Phase:             "Running",
+		Labels:            map[string]string{"app": "web"},
+		Message:           "Pod updated",
+		Reason:            "Container app restart count changed to 3",
+		StartupSeconds:    4.5,
+		LifetimeSeconds:   60,
+		Workload:          "Deployment/web",
+		Zone:              "eu-west-1a",
+		QOSClass:          "Burstable",
+		Priority:          &priority,
+		Services:          []string{"web"},
+		ContainerResults:  []ContainerResult{{Name: "app", ExitCode: 1, Reason: "Error"}},
+		PriorityClassName: "high",
+	}
+
+	tests := []struct {
+		name       string
+		cloudEvent bool
+	}{
+		{name: "plain"},
+		{name: "cloudevents", cloudEvent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emitted := event
+			if tt.cloudEvent {
+				emitted.cloudEvent = newCloudEventContext(event, "prod")
+			}
+
+			var out bytes.Buffer
+			sink := NewSlogSink(slog.New(slog.NewJSONHandler(&out, nil)))
+			if err := sink.Emit(emitted); err != nil {
+				t.Fatal(err)
+			}
+
+			var record map[string]any
+			if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+				t.Fatalf("record is not JSON: %v\n%s", err, out.String())
+			}
+			if record["level"] != "WARN" || record["msg"] != "Pod updated" {
+				t.Errorf("record level/msg = %v/%v, want WARN/Pod updated", record["level"], record["msg"])
+			}
+
+			want, err := json.Marshal(emitted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields map[string]any
+			if err := json.Unmarshal(want, &fields); err != nil {
+				t.Fatal(err)
+			}
+			for key, value := range fields {
+				if key == "time" {
+					key = "event_time"
+				}
+				if !reflect.DeepEqual(record[key], value) {
+					t.Errorf("record %s = %v, want %v", key, record[key], value)
+				}
+			}
+			if tt.cloudEvent && record["specversion"] != "1.0" {
+				t.Errorf("record specversion = %v, want the CloudEvents envelope", record["specversion"])
+			}
+		})
+	}
+}
+
+func TestSlogSinkTextRecord(t *testing.T) {
+	var out bytes.Buffer
+	sink := NewSlogSink(slog.New(slog.NewTextHandler(&out, nil)))
+	event := PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop", Phase: "Pending",
+		Message: "New pod created", FirstSeen: true, Labels: map[string]string{"app": "web"}}
+	if err := sink.Emit(event); err != nil {
+		t.Fatal(err)
+	}
+
+	line := out.String()
+	for _, want := range []string{`msg="New pod created"`, "event_type=ADDED", "pod_name=web", "first_seen=true", `labels="{\"app\":\"web\"}"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("text record %q is missing %s", line, want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const lokiPushPath = "/loki/api/v1/push"
+
+var lokiLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// lokiSink batches events and pushes them to Loki's HTTP push API. Each event
+// becomes one log line (the event JSON) in a stream labelled by the configured
+// event fields plus any static labels. Delivery is best effort: failures are
+// logged and the batch is dropped, and events are dropped with a warning when
+// the buffer is full so the watch loop never blocks on Loki.
+type lokiSink struct {
+	url          string
+	streamLabels []string
+	staticLabels map[string]string
+	batchSize    int
+	batchWait    time.Duration
+	client       *http.Client
+	logger       *log.Logger
+
+	mu      sync.RWMutex
+	closed  bool
+	events  chan PodEvent
+	flushes chan chan int
+	done    chan struct{}
+
+	// lastPushed holds the newest timestamp pushed per stream; Loki rejects
+	// entries older than that within a stream.
+	lastPushed map[string]time.Time
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// newLokiSinkFromEnv builds the sink from LOKI_URL and friends. It returns
+// nil when LOKI_URL is unset.
+func newLokiSinkFromEnv(logger *log.Logger) (*lokiSink, error) {
+	url := strings.TrimRight(strings.TrimSpace(os.Getenv("LOKI_URL")), "/")
+	if url == "" {
+		return nil, nil
+	}
+	if !strings.HasSuffix(url, lokiPushPath) {
+		url += lokiPushPath
+	}
+
+	streamLabels := []string{"namespace", "event_type"}
+	if value := strings.TrimSpace(os.Getenv("LOKI_STREAM_LABELS")); value != "" {
+		streamLabels = nil
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if _, ok := lokiStreamLabelValue(PodEvent{}, name); !ok {
+				return nil, fmt.Errorf("unsupported LOKI_STREAM_LABELS entry %q (supported: namespace, event_type, phase, node_name)", name)
+			}
+			streamLabels = append(streamLabels, name)
+		}
+	}
+
+	staticLabels := make(map[string]string)
+	if value := strings.TrimSpace(os.Getenv("LOKI_LABELS")); value != "" {
+		for _, pair := range strings.Split(value, ",") {
+			name, labelValue, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || !lokiLabelName.MatchString(name) {
+				return nil, fmt.Errorf("invalid LOKI_LABELS entry %q, expected name=value", pair)
+			}
+			staticLabels[name] = labelValue
+		}
+	}
+
+	batchSize := envInt("LOKI_BATCH_SIZE", 100)
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	return &lokiSink{
+		url:          url,
+		streamLabels: streamLabels,
+		staticLabels: staticLabels,
+		batchSize:    batchSize,
+		batchWait:    envDuration("LOKI_BATCH_WAIT", time.Second),
+		client:       &http.Client{Timeout: 10 * time.Second},
+		logger:       logger,
+		events:       make(chan PodEvent, 10*batchSize),
+		flushes:      make(chan chan int),
+		done:         make(chan struct{}),
+		lastPushed:   make(map[string]time.Time),
+	}, nil
+}
+
+func lokiStreamLabelValue(event PodEvent, name string) (string, bool) {
+	switch name {
+	case "namespace":
+		return event.Namespace, true
+	case "event_type":
+		return event.EventType, true
+	case "phase":
+		return event.Phase, true
+	case "node_name":
+		return event.NodeName, true
+	}
+	return "", false
+}
+
+// Emit queues an event for the next push without blocking.
+func (s *lokiSink) Emit(event PodEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("Loki buffer full")
+	}
+}
+
+// run batches queued events until Close is called, pushing a batch when it
+// reaches LOKI_BATCH_SIZE, when LOKI_BATCH_WAIT elapses or on a flush.
+func (s *lokiSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.batchWait)
+	defer ticker.Stop()
+
+	batch := make([]PodEvent, 0, s.batchSize)
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				s.push(batch)
+				return
+			}
+			batch = s.add(batch, event)
+		case ack := <-s.flushes:
+			// Only what is queued now; later events wait for the next batch.
+			queued := len(s.events)
+			delivered := len(batch) + queued
+			for i := 0; i < queued; i++ {
+				batch = s.add(batch, <-s.events)
+			}
+			s.push(batch)
+			batch = batch[:0]
+			ack <- delivered
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.push(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// add appends event to batch, pushing the batch once it is full.
+func (s *lokiSink) add(batch []PodEvent, event PodEvent) []PodEvent {
+	batch = append(batch, event)
+	if len(batch) >= s.batchSize {
+		s.push(batch)
+		batch = batch[:0]
+	}
+	return batch
+}
+
+func (s *lokiSink) flush(ctx context.Context) (int, error) {
+	return requestFlush(ctx, s.flushes, s.done)
+}
+
+// Close stops accepting events and waits up to timeout for the final push.
+func (s *lokiSink) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		s.logger.Println("⚠️  Timed out flushing events to Loki")
+	}
+}
+
+func (s *lokiSink) pending() int {
+	return len(s.events)
+}
+
+func (s *lokiSink) push(batch []PodEvent) {
+	if len(batch) == 0 {
+		return
+	}
+
+	// Loki requires entries within a stream to be in timestamp order.
+	sorted := make([]PodEvent, len(batch))
+	copy(sorted, batch)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	streams := make(map[string]*lokiStream)
+	var keys []string
+	for _, event := range sorted {
+		line, err := json.Marshal(event)
+		if err != nil {
+			s.logger.Printf("❌ Failed to marshal event for Loki: %v", err)
+			continue
+		}
+
+		labels := make(map[string]string, len(s.staticLabels)+len(s.streamLabels))
+		for name, value := range s.staticLabels {
+			labels[name] = value
+		}
+		for _, name := range s.streamLabels {
+			value, _ := lokiStreamLabelValue(event, name)
+			if value != "" {
+				labels[name] = value
+			}
+		}
+
+		key := lokiStreamKey(labels)
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			keys = append(keys, key)
+		}
+
+		timestamp := event.Timestamp
+		if last := s.lastPushed[key]; timestamp.Before(last) {
+			timestamp = last
+		}
+		s.lastPushed[key] = timestamp
+
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(timestamp.UnixNano(), 10), string(line)})
+	}
+
+	request := lokiPushRequest{Streams: make([]lokiStream, 0, len(keys))}
+	for _, key := range keys {
+		request.Streams = append(request.Streams, *streams[key])
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		s.logger.Printf("❌ Failed to marshal Loki push request: %v", err)
+		return
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		s.logger.Printf("❌ Failed to push %d events to Loki: %v", len(batch), err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		s.logger.Printf("❌ Loki rejected %d events: %s: %s", len(batch), resp.Status, strings.TrimSpace(string(message)))
+	}
+}
+
+// lokiStreamKey renders a label set as a stable {a="1",b="2"} string.
+func lokiStreamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
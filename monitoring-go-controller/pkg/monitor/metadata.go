@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultIgnoredAnnotations are annotations that change on every apply and
+// would otherwise drown out the interesting ones.
+const defaultIgnoredAnnotations = "kubectl.kubernetes.io/last-applied-configuration"
+
+// parseIgnoredAnnotations parses a comma-separated list of annotation keys.
+func parseIgnoredAnnotations(spec string) map[string]bool {
+	ignored := make(map[string]bool)
+	for _, key := range strings.Split(spec, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			ignored[key] = true
+		}
+	}
+	return ignored
+}
+
+// metadataChangeReasons reports labels, and with --track-annotations
+// annotations, that were added, removed or changed between two pod
+// versions. Annotation values can be large, so only their keys are named.
+func (pm *PodMonitor) metadataChangeReasons(oldLabels, newLabels, oldAnnotations, newAnnotations map[string]string) []string {
+	reasons := mapChangeReasons("Label", oldLabels, newLabels, nil, true)
+	if pm.trackAnnotations {
+		reasons = append(reasons, mapChangeReasons("Annotation", oldAnnotations, newAnnotations, pm.ignoredAnnotations, false)...)
+	}
+	return reasons
+}
+
+// mapChangeReasons compares two label or annotation maps in key order,
+// skipping ignored keys.
+func mapChangeReasons(kind string, oldMap, newMap map[string]string, ignored map[string]bool, withValues bool) []string {
+	keys := make([]string, 0, len(oldMap)+len(newMap))
+	for key := range oldMap {
+		keys = append(keys, key)
+	}
+	for key := range newMap {
+		if _, existed := oldMap[key]; !existed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var reasons []string
+	for _, key := range keys {
+		if ignored[key] {
+			continue
+		}
+		oldValue, existed := oldMap[key]
+		newValue, exists := newMap[key]
+		switch {
+		case !existed && withValues:
+			reasons = append(reasons, fmt.Sprintf("%s %s added with value %s", kind, key, newValue))
+		case !existed:
+			reasons = append(reasons, fmt.Sprintf("%s %s added", kind, key))
+		case !exists:
+			reasons = append(reasons, fmt.Sprintf("%s %s removed", kind, key))
+		case oldValue == newValue:
+		case withValues:
+			reasons = append(reasons, fmt.Sprintf("%s %s changed from %s to %s", kind, key, oldValue, newValue))
+		default:
+			reasons = append(reasons, fmt.Sprintf("%s %s changed", kind, key))
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics are registered on the default Prometheus registry, so they are
+// shared by every PodMonitor in the process.
+var (
+	podEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pod_events_total",
+		Help: "Pod monitor events by type and namespace, counted before output filters are applied.",
+	}, []string{"type", "namespace"})
+
+	podsWatched = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "pods_watched",
+		Help: "Number of pods currently tracked by the pod monitor.",
+	})
+
+	watchReconnectsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "watch_reconnects_total",
+		Help: "Number of times a pod watch was closed and re-established.",
+	})
+
+	podStartupSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pod_startup_seconds",
+		Help:    "Time from pod creation to its first Ready condition.",
+		Buckets: []float64{1, 2, 5, 10, 15, 30, 60, 120, 180, 300},
+	})
+
+	podLifetimeSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pod_lifetime_seconds",
+		Help:    "Time from pod creation to its deletion.",
+		Buckets: []float64{1, 10, 30, 60, 300, 900, 3600, 6 * 3600, 24 * 3600, 7 * 24 * 3600},
+	})
+
+	podPhaseDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pod_phase_duration_seconds",
+		Help:    "Time pods spent in a phase before moving to the next one.",
+		Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+	}, []string{"phase"})
+
+	eventProcessingSeconds = promauto.NewSummary(prometheus.SummaryOpts{
+		Name:       "event_processing_seconds",
+		Help:       "Time spent handling one pod watch event, including emitting it to the sinks.",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+	})
+
+	eventLagSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "event_lag_seconds",
+		Help: "Time between the latest pod condition transition and the monitor handling it, at one-second resolution.",
+	})
+)
+
+// countEvent counts event in pod_events_total. With both metrics and OTel
+// export enabled, the trace ID of the event's emit span is attached as an
+// exemplar, linking a spike in the counter to the traces behind it.
+func (pm *PodMonitor) countEvent(event PodEvent, span trace.SpanContext) {
+	counter := podEventsTotal.WithLabelValues(event.EventType, event.Namespace)
+	if pm.otel == nil || pm.metricsAddr == "" || !span.HasTraceID() {
+		counter.Inc()
+		return
+	}
+	counter.(prometheus.ExemplarAdder).AddWithExemplar(1, prometheus.Labels{"trace_id": span.TraceID().String()})
+}
@@ -0,0 +1,1733 @@
+// Package monitor watches pods (and optionally nodes, workloads and claims)
+// in a Kubernetes cluster and emits a PodEvent for every change worth
+// reporting. Programs embedding it build a PodMonitor with NewPodMonitor or
+// NewPodMonitorWithClient, consume events from Events or an EventSink added
+// with AddSink, and call Start.
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+type PodEvent struct {
+	// SchemaVersion is the JSON schema version the event is written in; set
+	// on every emitted event.
+	SchemaVersion int `json:"schema_version,omitempty"`
+
+	Timestamp time.Time         `json:"timestamp"`
+	EventType string            `json:"event_type"`
+	PodName   string            `json:"pod_name"`
+	Namespace string            `json:"namespace"`
+	PodIP     string            `json:"pod_ip,omitempty"`
+	NodeName  string            `json:"node_name,omitempty"`
+	Phase     string            `json:"phase"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Message   string            `json:"message"`
+	Reason    string            `json:"reason,omitempty"`
+	ProbeType string            `json:"probe_type,omitempty"`
+	Counts    map[string]int    `json:"counts,omitempty"`
+
+	PhaseDurationSeconds float64    `json:"phase_duration_seconds,omitempty"`
+	StartupSeconds       float64    `json:"startup_seconds,omitempty"`
+	LifetimeSeconds      float64    `json:"lifetime_seconds,omitempty"`
+	KubeEvent            *KubeEvent `json:"k8s_event,omitempty"`
+	// Workload is "Kind/name" on workload rollout events.
+	Workload string `json:"workload,omitempty"`
+	// PVC describes the claim on PVC_* events.
+	PVC *PVCEvent `json:"pvc,omitempty"`
+	// ContainerResults is set when a pod finishes and when a finished
+	// pod is deleted.
+	ContainerResults []ContainerResult `json:"container_results,omitempty"`
+	// Synthetic marks events reconstructed from a relist rather than
+	// received from the watch.
+	Synthetic bool `json:"synthetic,omitempty"`
+	// FirstSeen marks the one ADDED event announcing a pod, whichever watch
+	// event or relist it was first observed in.
+	FirstSeen      bool   `json:"first_seen,omitempty"`
+	Important      bool   `json:"important,omitempty"`
+	ServiceAccount string `json:"service_account,omitempty"`
+	Zone           string `json:"zone,omitempty"`
+	InstanceType   string `json:"instance_type,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+
+	QOSClass          string `json:"qos_class,omitempty"`
+	PriorityClassName string `json:"priority_class_name,omitempty"`
+	Priority          *int32 `json:"priority,omitempty"`
+
+	Usage  *ResourceUsage    `json:"usage,omitempty"`
+	Config map[string]string `json:"config,omitempty"`
+
+	// Services names the Services selecting the pod, on events where its
+	// readiness changed.
+	Services []string `json:"services,omitempty"`
+
+	// timeFormat is how MarshalJSON writes Timestamp; set by logEvent.
+	timeFormat string
+	// cloudEvent, set by logEvent with --output-format=cloudevents, makes
+	// MarshalJSON wrap the event in a CloudEvents envelope.
+	cloudEvent *cloudEventContext
+}
+
+// severityWarning marks events that usually need attention, such as a
+// container in CrashLoopBackOff or one that was OOMKilled. severityCritical
+// marks one-shot alerts such as a container passing the restart threshold.
+const (
+	severityWarning  = "warning"
+	severityCritical = "critical"
+)
+
+type PodMonitor struct {
+	clientset   kubernetes.Interface
+	namespaces  []string
+	logger      *log.Logger
+	stopCh      chan struct{}
+	maxRetries  int
+	watchEvents bool
+	nodeWatcher *nodeWatcher
+	// workloadWatchers follow StatefulSet and DaemonSet rollouts when
+	// enabled.
+	workloadWatchers []*workloadWatcher
+	// pvcWatchers report PersistentVolumeClaim phase changes and claims
+	// Pending longer than pvcPendingThreshold when enabled.
+	pvcWatchers         []*pvcWatcher
+	pvcPendingThreshold time.Duration
+
+	correlateEvents bool
+
+	// leaderElection is nil unless --leader-elect is set; leading is true
+	// while this replica holds the lease.
+	leaderElection *leaderElection
+	leading        atomic.Bool
+
+	// fieldSelector is the parsed --field-selector, empty when unset.
+	fieldSelector string
+
+	// watchMode is watchModeWatch (raw List+Watch) or watchModeInformer.
+	watchMode string
+
+	backoff reconnectBackoff
+	// retryForever is set by --on-watch-failure=retry-forever: pod watches
+	// never give up after maxRetries.
+	retryForever bool
+	// stablePeriod is how long a pod watch must stay open before its failure
+	// count starts over; zero leaves that to incoming events.
+	stablePeriod time.Duration
+	// now is the clock stablePeriod is measured with; tests replace it.
+	now func() time.Time
+	// strictRBAC makes a failed pod permission check at startup fatal.
+	strictRBAC bool
+
+	flap flapDetection
+
+	timestamps timestamps
+	// cloudEvents wraps events sent to the sinks in CloudEvents envelopes.
+	cloudEvents bool
+	// trackAnnotations adds annotation changes, except ignoredAnnotations,
+	// to MODIFIED reasons. Label changes are always reported.
+	trackAnnotations   bool
+	ignoredAnnotations map[string]bool
+	// shutdownTimeout bounds how long buffered events are flushed to the
+	// asynchronous sinks on shutdown.
+	shutdownTimeout time.Duration
+	// schemaVersion is stamped on every emitted event.
+	schemaVersion int
+	// runDuration ends Start cleanly after this long when positive.
+	runDuration time.Duration
+	// eventsEmitted counts events that passed the filters, for the
+	// --duration summary.
+	eventsEmitted atomic.Int64
+
+	// modifiedThrottle coalesces uninformative MODIFIED events per pod.
+	modifiedThrottle time.Duration
+	// restartAlertThreshold emits RESTART_THRESHOLD when a container's
+	// restart count passes it. Zero disables the alert.
+	restartAlertThreshold int32
+	relistInterval        time.Duration
+	// listTimeout bounds each pod List call.
+	listTimeout time.Duration
+
+	metricsAddr string
+	healthAddr  string
+	otel        *otelExporter
+	tracer      trace.Tracer
+
+	// connected is set once the Kubernetes API has been reached.
+	connected atomic.Bool
+
+	// watchers holds one pod watcher per namespace, or a single cluster-wide
+	// watcher when watching all namespaces or using the client_side strategy.
+	watchers []*podWatcher
+
+	podCountInterval time.Duration
+	// resyncPeriod enables SYNC events re-emitting every tracked pod.
+	resyncPeriod time.Duration
+	// summaryInterval enables HEALTH_SUMMARY events listing the summaryTopN
+	// pods with the most restarts.
+	summaryInterval time.Duration
+	summaryTopN     int
+	// sinkHeartbeatInterval enables HEALTH_SUMMARY heartbeats sent to the
+	// connection-based sinks only.
+	sinkHeartbeatInterval time.Duration
+
+	// importantLabelKey/Value mark pods whose events are always emitted.
+	importantLabelKey   string
+	importantLabelValue string
+
+	execHook *execHook
+	loki     *lokiSink
+	webhook  *webhookSink
+	slack    *slackSink
+	teams    *teamsSink
+	kafka    *kafkaSink
+	redis    *redisSink
+	nats     *natsSink
+	file     *fileSink
+	store    *eventStore
+
+	// apiToken, when set, is required as a bearer token on GET /events.
+	apiToken string
+
+	// recent holds the last --event-buffer-size events; nil when disabled.
+	recent *recentEvents
+
+	// sinks receive every event that passes the filters, in order.
+	// asyncSinks are the subset that deliver from their own goroutine and are
+	// started and flushed by Start.
+	sinks      []EventSink
+	asyncSinks []asyncSink
+
+	// serviceAccountFilter limits emitted pod events to pods running as this
+	// service account. All pods are still tracked.
+	serviceAccountFilter string
+	// filters drop events after the watch; swapped on a config reload.
+	filters atomic.Pointer[eventFilters]
+	// logLevel filters structured log records; also changed on a reload.
+	logLevel *slog.LevelVar
+
+	// ownerFilter is only set with --owner-kind and --owner-name.
+	ownerFilter *ownerFilter
+
+	// scopeMu guards namespaces and watchers, which a reload replaces while
+	// the watches are stopped. fieldSelector, ownerFilter and the PVC and
+	// workload watchers are only read by running watches.
+	scopeMu sync.RWMutex
+	// config is the configuration as last applied by a reload; rescope
+	// hands a changed watch scope to runWatches.
+	config  Config
+	rescope chan watchScope
+
+	watchStrategy string
+	clusterName   string
+	// impersonate is the --as user API requests are made as, if any.
+	impersonate string
+
+	// nodeLabels is only set when ENRICH_NODE_LABELS is on.
+	nodeLabels *nodeLabelCache
+	// services is only set when ENRICH_SERVICES is on.
+	services *serviceCache
+
+	// logEvents controls whether events are written to stdout. Embedders
+	// consuming Events() can turn it off with LOG_EVENTS=false.
+	logEvents bool
+	// logSink writes events to stdout; it is in sinks when logEvents is on.
+	logSink EventSink
+
+	// subscribers are the open Events() channels. eventsDone is closed, and
+	// subscribersClosed set, once the monitor has stopped.
+	subscribersMu     sync.Mutex
+	subscribers       map[chan PodEvent]struct{}
+	subscribersClosed bool
+	eventsDone        chan struct{}
+	eventChannelSize  int
+	eventsDropped     atomic.Int64
+
+	terminalLingerThreshold time.Duration
+	pendingThreshold        time.Duration
+	terminatingSlack        time.Duration
+	clockSkewTolerance      time.Duration
+
+	// metricsClient is only set when ENABLE_USAGE is on.
+	metricsClient metricsclientset.Interface
+	usageInterval time.Duration
+}
+
+// NewPodMonitor builds a monitor using the in-cluster configuration, falling
+// back to cfg.Kubeconfig or ~/.kube/config.
+func NewPodMonitor(cfg Config) (*PodMonitor, error) {
+	config, err := kubeClientConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %v", err)
+	}
+
+	var metricsClient metricsclientset.Interface
+	if cfg.EnableUsage {
+		metricsClient, err = metricsclientset.NewForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metrics client: %v", err)
+		}
+	}
+
+	return newPodMonitor(cfg, clientset, metricsClient)
+}
+
+// NewPodMonitorWithClient builds a monitor around an existing client, such as
+// a fake clientset in tests. Other settings use the same defaults as the
+// command line; an empty namespace watches all namespaces. Pod usage
+// reporting (ENABLE_USAGE) is not available with an injected client.
+func NewPodMonitorWithClient(client kubernetes.Interface, namespace string) (*PodMonitor, error) {
+	cfg, err := DefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Namespaces = []string{namespace}
+	return newPodMonitor(cfg, client, nil)
+}
+
+func newPodMonitor(cfg Config, clientset kubernetes.Interface, metricsClient metricsclientset.Interface) (*PodMonitor, error) {
+	scope, err := newWatchScope(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.WatchMode != watchModeWatch && cfg.WatchMode != watchModeInformer {
+		return nil, fmt.Errorf("invalid watch mode %q: must be %s or %s", cfg.WatchMode, watchModeWatch, watchModeInformer)
+	}
+
+	if cfg.MaxRetries < 1 {
+		return nil, fmt.Errorf("max retries must be at least 1, got %d", cfg.MaxRetries)
+	}
+
+	if cfg.OnWatchFailure != watchFailureExit && cfg.OnWatchFailure != watchFailureRetryForever {
+		return nil, fmt.Errorf("invalid watch failure policy %q: must be %s or %s", cfg.OnWatchFailure, watchFailureExit, watchFailureRetryForever)
+	}
+	if cfg.OutputFormat != outputFormatJSON && cfg.OutputFormat != outputFormatCloudEvents {
+		return nil, fmt.Errorf("invalid output format %q: must be %s or %s", cfg.OutputFormat, outputFormatJSON, outputFormatCloudEvents)
+	}
+	if cfg.StablePeriod < 0 {
+		return nil, fmt.Errorf("stable period must not be negative, got %v", cfg.StablePeriod)
+	}
+	if cfg.TerminatingSlack < 0 {
+		return nil, fmt.Errorf("terminating slack must not be negative, got %v", cfg.TerminatingSlack)
+	}
+
+	if err := cfg.Backoff.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.ListTimeout <= 0 {
+		return nil, fmt.Errorf("list timeout must be positive, got %v", cfg.ListTimeout)
+	}
+
+	timestamps, err := parseTimestamps(cfg.TimeFormat, cfg.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	var errOut io.Writer = os.Stdout
+	if cfg.SplitStreams {
+		errOut = os.Stderr
+	}
+	color, err := useColor(cfg.Color, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	level, err := parseLogLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(level)
+	logger, logSink, err := newLogging(os.Stdout, errOut, cfg.LogFormat, logLevel, cfg.LegacyLog, timestamps, color, cfg.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.KubeQPS > maxSensibleKubeQPS || cfg.KubeBurst > maxSensibleKubeBurst {
+		logger.Printf("⚠️  Kubernetes client rate limit of %v QPS / %d burst is very high and may overload the API server",
+			cfg.KubeQPS, cfg.KubeBurst)
+	}
+
+	watchStrategy := strings.TrimSpace(cfg.WatchStrategy)
+	if watchStrategy == "" {
+		watchStrategy = watchStrategyServerSide
+	}
+	if watchStrategy != watchStrategyServerSide && watchStrategy != watchStrategyClientSide {
+		return nil, fmt.Errorf("invalid watch strategy %q: must be %s or %s",
+			watchStrategy, watchStrategyServerSide, watchStrategyClientSide)
+	}
+
+	loki, err := newLokiSinkFromEnv(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhookTLS *tls.Config
+	if cfg.WebhookURL != "" || cfg.SlackWebhookURL != "" || cfg.TeamsWebhookURL != "" {
+		webhookTLS, err = newWebhookTLSConfig(cfg.WebhookCAFile, cfg.WebhookInsecureSkipVerify, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	webhook, err := newWebhookSink(cfg.WebhookURL, webhookTLS, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	slack, err := newSlackSink(cfg.SlackWebhookURL, webhookTLS, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := newTeamsSink(cfg.TeamsWebhookURL, webhookTLS, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := openEventStore(cfg.DBPath, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := newFileSink(cfg.OutputFile, cfg.OutputFileMaxSizeMB, cfg.OutputFileMaxBackups, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	kafka, err := newKafkaSink(cfg.KafkaBrokers, cfg.KafkaTopic, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	redis, err := newRedisSink(cfg.RedisAddr, cfg.RedisChannel, cfg.RedisTLS, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	nats, err := newNATSSink(cfg.NATSURL, cfg.NATSSubject, cfg.NATSCreds, cfg.NATSCA, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	otel, err := newOTelExporter(cfg.OTelEndpoint, cfg.ClusterName, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodeLabels *nodeLabelCache
+	if cfg.EnrichNodeLabels {
+		nodeLabels = newNodeLabelCache(clientset, cfg.NodeLabelRefresh, logger)
+	}
+
+	filters, err := newEventFilters(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaVersion, err := parseSchemaVersion(cfg.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	importantKey, importantValue, err := parseLabelMatch(cfg.ImportantLabel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid important label: %v", err)
+	}
+
+	pm := &PodMonitor{
+		clientset:  clientset,
+		namespaces: scope.namespaces,
+		logger:     logger,
+		stopCh:     make(chan struct{}),
+		maxRetries: cfg.MaxRetries,
+
+		fieldSelector: scope.fieldSelector,
+		watchMode:     cfg.WatchMode,
+		backoff:       cfg.Backoff,
+		retryForever:  cfg.OnWatchFailure == watchFailureRetryForever,
+		stablePeriod:  cfg.StablePeriod,
+		now:           time.Now,
+		strictRBAC:    cfg.StrictRBAC,
+		flap:          cfg.Flap,
+
+		timestamps:       timestamps,
+		cloudEvents:      cfg.OutputFormat == outputFormatCloudEvents,
+		shutdownTimeout:  cfg.ShutdownTimeout,
+		runDuration:      cfg.Duration,
+		schemaVersion:    schemaVersion,
+		modifiedThrottle: cfg.ModifiedThrottle,
+
+		trackAnnotations:      cfg.TrackAnnotations,
+		ignoredAnnotations:    parseIgnoredAnnotations(cfg.IgnoreAnnotations),
+		restartAlertThreshold: int32(cfg.RestartAlertThreshold),
+		relistInterval:        cfg.RelistInterval,
+		listTimeout:           cfg.ListTimeout,
+		metricsAddr:           cfg.MetricsAddr,
+		otel:                  otel,
+		tracer:                otel.tracer(),
+		healthAddr:            cfg.HealthAddr,
+		watchEvents:           cfg.WatchEvents,
+		correlateEvents:       cfg.CorrelateEvents,
+
+		podCountInterval: cfg.PodCountInterval,
+		resyncPeriod:     cfg.ResyncPeriod,
+		summaryInterval:  cfg.SummaryInterval,
+		summaryTopN:      cfg.SummaryTopN,
+
+		sinkHeartbeatInterval: cfg.SinkHeartbeatInterval,
+
+		importantLabelKey:   importantKey,
+		importantLabelValue: importantValue,
+
+		execHook: newExecHookFromEnv(logger),
+		loki:     loki,
+		webhook:  webhook,
+		slack:    slack,
+		teams:    teams,
+		kafka:    kafka,
+		redis:    redis,
+		nats:     nats,
+		file:     file,
+		store:    store,
+		apiToken: cfg.APIToken,
+		recent:   newRecentEvents(cfg.EventBufferSize),
+
+		serviceAccountFilter: strings.TrimSpace(cfg.ServiceAccountFilter),
+		ownerFilter:          scope.ownerFilter,
+		logLevel:             logLevel,
+
+		watchStrategy: watchStrategy,
+		clusterName:   strings.TrimSpace(cfg.ClusterName),
+		impersonate:   strings.TrimSpace(cfg.ImpersonateUser),
+		nodeLabels:    nodeLabels,
+
+		config:           cfg,
+		rescope:          make(chan watchScope, 1),
+		logEvents:        cfg.LogEvents,
+		logSink:          logSink,
+		subscribers:      make(map[chan PodEvent]struct{}),
+		eventsDone:       make(chan struct{}),
+		eventChannelSize: eventChannelSize(cfg.EventChannelSize),
+
+		terminalLingerThreshold: cfg.TerminalLingerThreshold,
+		pendingThreshold:        cfg.PendingThreshold,
+		terminatingSlack:        cfg.TerminatingSlack,
+		clockSkewTolerance:      cfg.ClockSkewTolerance,
+
+		metricsClient: metricsClient,
+		usageInterval: cfg.UsageInterval,
+	}
+
+	if pm.execHook != nil {
+		pm.sinks = append(pm.sinks, pm.execHook)
+	}
+	if loki != nil {
+		pm.asyncSinks = append(pm.asyncSinks, loki)
+	}
+	if webhook != nil {
+		pm.asyncSinks = append(pm.asyncSinks, webhook)
+	}
+	if slack != nil {
+		pm.asyncSinks = append(pm.asyncSinks, slack)
+	}
+	if teams != nil {
+		pm.asyncSinks = append(pm.asyncSinks, teams)
+	}
+	if kafka != nil {
+		pm.asyncSinks = append(pm.asyncSinks, kafka)
+	}
+	if redis != nil {
+		pm.asyncSinks = append(pm.asyncSinks, redis)
+	}
+	if nats != nil {
+		pm.asyncSinks = append(pm.asyncSinks, nats)
+	}
+	if file != nil {
+		pm.asyncSinks = append(pm.asyncSinks, file)
+	}
+	if store != nil {
+		pm.asyncSinks = append(pm.asyncSinks, store)
+	}
+	for _, sink := range pm.asyncSinks {
+		pm.sinks = append(pm.sinks, sink)
+	}
+	if pm.logEvents {
+		pm.sinks = append(pm.sinks, logSink)
+	}
+
+	if cfg.LeaderElect {
+		pm.leaderElection, err = newLeaderElection(cfg.LeaderElectionLease, cfg.LeaderElectionNamespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.WatchNodes {
+		pm.nodeWatcher = newNodeWatcher(pm)
+	}
+
+	if cfg.EnrichServices {
+		pm.services = newServiceCache(clientset, pm.watchedNamespaces, cfg.ServiceRefresh, logger)
+	}
+
+	pm.filters.Store(filters)
+	pm.pvcPendingThreshold = cfg.PVCPendingThreshold
+	pm.watchers, pm.pvcWatchers, pm.workloadWatchers = pm.buildWatchers(cfg, scope.namespaces)
+
+	return pm, nil
+}
+
+// buildWatchers creates a pod watcher per namespace, or a single one for all
+// namespaces, and the PVC and workload watchers enabled in cfg alongside each.
+func (pm *PodMonitor) buildWatchers(cfg Config, namespaces []string) ([]*podWatcher, []*pvcWatcher, []*workloadWatcher) {
+	var watchers []*podWatcher
+	if len(namespaces) == 0 || pm.watchStrategy == watchStrategyClientSide {
+		watchers = []*podWatcher{newPodWatcher(pm, metav1.NamespaceAll)}
+	} else {
+		for _, namespace := range namespaces {
+			watchers = append(watchers, newPodWatcher(pm, namespace))
+		}
+	}
+
+	var workloadKinds []workloadKind
+	if cfg.WatchStatefulSets {
+		workloadKinds = append(workloadKinds, statefulSetKind(pm.clientset))
+	}
+	if cfg.WatchDaemonSets {
+		workloadKinds = append(workloadKinds, daemonSetKind(pm.clientset))
+	}
+
+	var pvcWatchers []*pvcWatcher
+	if cfg.WatchPVCs {
+		for _, w := range watchers {
+			pvcWatchers = append(pvcWatchers, newPVCWatcher(pm, w.namespace))
+		}
+	}
+
+	var workloadWatchers []*workloadWatcher
+	for _, kind := range workloadKinds {
+		for _, w := range watchers {
+			workloadWatchers = append(workloadWatchers, newWorkloadWatcher(pm, kind, w.namespace))
+		}
+	}
+	return watchers, pvcWatchers, workloadWatchers
+}
+
+// parseLabelMatch parses "key=value" or a bare "key" (match on presence).
+func parseLabelMatch(spec string) (string, string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return "", "", nil
+	}
+
+	key, value, _ := strings.Cut(spec, "=")
+	key = strings.TrimSpace(key)
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+	}
+	return key, strings.TrimSpace(value), nil
+}
+
+// isImportant reports whether a pod carries the configured IMPORTANT_LABEL.
+func (pm *PodMonitor) isImportant(labels map[string]string) bool {
+	if pm.importantLabelKey == "" {
+		return false
+	}
+	value, ok := labels[pm.importantLabelKey]
+	if !ok {
+		return false
+	}
+	return pm.importantLabelValue == "" || value == pm.importantLabelValue
+}
+
+// normalizeNamespaces trims stray whitespace, drops empty entries and
+// duplicates, and validates each name against the Kubernetes namespace naming
+// rules (RFC 1123 label). An empty result means all namespaces.
+func normalizeNamespaces(namespaces []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, namespace := range namespaces {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, "; "))
+		}
+		seen[namespace] = true
+		normalized = append(normalized, namespace)
+	}
+	return normalized, nil
+}
+
+// NamespaceLabel describes the namespaces the monitor watches, e.g. for a
+// startup log line.
+func (pm *PodMonitor) NamespaceLabel() string {
+	return namespaceLabel(pm.watchedNamespaces())
+}
+
+// namespaceLabel renders a namespace list for log lines.
+func namespaceLabel(namespaces []string) string {
+	if len(namespaces) == 0 {
+		return "all namespaces"
+	}
+	return strings.Join(namespaces, ",")
+}
+
+// namespaceFromEnv returns the NAMESPACE environment variable (a
+// comma-separated list), defaulting to devops-case-study when it is unset or
+// blank.
+func namespaceFromEnv() string {
+	namespace := strings.TrimSpace(os.Getenv("NAMESPACE"))
+	if namespace == "" {
+		namespace = "devops-case-study"
+	}
+	return namespace
+}
+
+// envString reads a string environment variable, falling back to def only
+// when the variable is unset, so an explicitly empty value is kept.
+func envString(key string, def string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return def
+}
+
+// envBool reads a boolean environment variable, falling back to def when the
+// variable is unset or not a valid boolean.
+func envBool(key string, def bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envInt reads an integer environment variable, falling back to def when the
+// variable is unset or not a valid integer.
+func envInt(key string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envFloat reads a floating-point environment variable, falling back to def
+// when the variable is unset or not a valid number.
+func envFloat(key string, def float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envDuration reads a duration environment variable such as "30s" or "5m",
+// falling back to def when the variable is unset or malformed.
+func envDuration(key string, def time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func (pm *PodMonitor) logEvent(event PodEvent) {
+	pm.logEventContext(context.Background(), event)
+}
+
+// logEventContext is logEvent with the emission traced as a child of any
+// span in ctx.
+func (pm *PodMonitor) logEventContext(ctx context.Context, event PodEvent) {
+	_, span := pm.startSpan(ctx, "emit event", "namespace", event.Namespace, "event_type", event.EventType)
+	defer span.End()
+	pm.countEvent(event, span.SpanContext())
+
+	event.SchemaVersion = pm.schemaVersion
+	event.Timestamp = event.Timestamp.In(pm.timestamps.location)
+	event.timeFormat = pm.timestamps.format
+
+	if pm.suppressed(event) {
+		return
+	}
+	pm.eventsEmitted.Add(1)
+
+	if pm.recent != nil {
+		pm.recent.add(event)
+	}
+
+	// Only the sinks see the CloudEvents envelope; Events() subscribers get
+	// the plain event.
+	sinkEvent := event
+	if pm.cloudEvents {
+		sinkEvent.cloudEvent = newCloudEventContext(event, pm.clusterName)
+	}
+	for _, sink := range pm.sinks {
+		if err := sink.Emit(sinkEvent); err != nil {
+			pm.logger.Printf("⚠️  Dropping %s event for %s/%s: %v", event.EventType, event.Namespace, event.PodName, err)
+		}
+	}
+
+	pm.publish(event)
+}
+
+// newPodEvent fills in the fields every pod-scoped event carries.
+func (pm *PodMonitor) newPodEvent(eventType string, pod *corev1.Pod) PodEvent {
+	event := PodEvent{
+		Timestamp: time.Now(),
+		EventType: eventType,
+		PodName:   pod.Name,
+		Namespace: pod.Namespace,
+		PodIP:     pod.Status.PodIP,
+		NodeName:  pod.Spec.NodeName,
+		Phase:     string(pod.Status.Phase),
+		Labels:    pod.Labels,
+		Important: pm.isImportant(pod.Labels),
+
+		ServiceAccount: pod.Spec.ServiceAccountName,
+
+		QOSClass:          string(pod.Status.QOSClass),
+		PriorityClassName: pod.Spec.PriorityClassName,
+		Priority:          pod.Spec.Priority,
+	}
+
+	if pm.nodeLabels != nil && pod.Spec.NodeName != "" {
+		topology := pm.nodeLabels.lookup(pod.Spec.NodeName)
+		event.Zone = topology.zone
+		event.InstanceType = topology.instanceType
+	}
+	return event
+}
+
+func (pm *PodMonitor) getChangeReason(oldPod, newPod *corev1.Pod) string {
+	var reasons []string
+
+	// Check phase changes
+	if oldPod.Status.Phase != newPod.Status.Phase {
+		reasons = append(reasons, fmt.Sprintf("Phase changed from %s to %s", oldPod.Status.Phase, newPod.Status.Phase))
+	}
+
+	if terminating := terminatingReason(oldPod, newPod); terminating != "" {
+		reasons = append(reasons, terminating)
+	}
+
+	// Check node assignment
+	switch {
+	case oldPod.Spec.NodeName == "" && newPod.Spec.NodeName != "":
+		reasons = append(reasons, fmt.Sprintf("Pod scheduled to node %s", newPod.Spec.NodeName))
+	case oldPod.Spec.NodeName != newPod.Spec.NodeName:
+		reasons = append(reasons, fmt.Sprintf("Pod rescheduled from node %s to node %s", oldPod.Spec.NodeName, newPod.Spec.NodeName))
+	}
+
+	// Check container status changes
+	reasons = append(reasons, pm.containerStatusReasons(newPod.UID, "", oldPod.Status.ContainerStatuses, newPod.Status.ContainerStatuses)...)
+	reasons = append(reasons, pm.containerStatusReasons(newPod.UID, "init:", oldPod.Status.InitContainerStatuses, newPod.Status.InitContainerStatuses)...)
+	reasons = append(reasons, pm.containerStatusReasons(newPod.UID, "ephemeral:", oldPod.Status.EphemeralContainerStatuses, newPod.Status.EphemeralContainerStatuses)...)
+
+	reasons = append(reasons, imageChangeReasons(oldPod.Spec.Containers, newPod.Spec.Containers)...)
+	reasons = append(reasons, pm.metadataChangeReasons(oldPod.Labels, newPod.Labels, oldPod.Annotations, newPod.Annotations)...)
+	reasons = append(reasons, imagePullReasons(oldPod.Status.InitContainerStatuses, newPod.Status.InitContainerStatuses, "Init container")...)
+	reasons = append(reasons, imagePullReasons(oldPod.Status.ContainerStatuses, newPod.Status.ContainerStatuses, "Container")...)
+	reasons = append(reasons, imagePullReasons(oldPod.Status.EphemeralContainerStatuses, newPod.Status.EphemeralContainerStatuses, "Ephemeral container")...)
+
+	// Check condition changes. An unschedulable pod is reported with the
+	// scheduler's message instead of the bare PodScheduled status.
+	unschedulable := unschedulableReason(oldPod, newPod)
+	if unschedulable != "" {
+		reasons = append(reasons, unschedulable)
+	}
+	for _, condition := range newPod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && unschedulable != "" {
+			continue
+		}
+		found := false
+		for _, oldCondition := range oldPod.Status.Conditions {
+			if condition.Type == oldCondition.Type {
+				found = true
+				if condition.Status != oldCondition.Status {
+					reasons = append(reasons, fmt.Sprintf("Condition %s changed to %s", condition.Type, condition.Status))
+				}
+				break
+			}
+		}
+		if !found {
+			reasons = append(reasons, fmt.Sprintf("New condition %s: %s", condition.Type, condition.Status))
+		}
+	}
+
+	if len(reasons) == 0 {
+		return genericChangeReason
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+// containerStatusReasons reports readiness, restart and waiting changes of
+// one kind of container, matching old and new statuses by name so that
+// containers added or removed in between are skipped. prefix is prepended to
+// the container name, e.g. "init:" reports "Container init:setup ...".
+func (pm *PodMonitor) containerStatusReasons(uid types.UID, prefix string, oldStatuses, newStatuses []corev1.ContainerStatus) []string {
+	oldByName := make(map[string]corev1.ContainerStatus, len(oldStatuses))
+	for _, status := range oldStatuses {
+		oldByName[status.Name] = status
+	}
+
+	var reasons []string
+	for _, container := range newStatuses {
+		oldContainer, existed := oldByName[container.Name]
+		if !existed {
+			continue
+		}
+		name := prefix + container.Name
+
+		if container.Ready != oldContainer.Ready {
+			reason := fmt.Sprintf("Container %s readiness changed to %v", name, container.Ready)
+			if !container.Ready {
+				if probeReason := pm.probeFailureReason(uid, container.Name, "readiness"); probeReason != "" {
+					reason = probeReason
+				}
+			} else {
+				pm.takeProbeFailure(uid, container.Name, "readiness")
+			}
+			reasons = append(reasons, reason)
+		}
+		if container.RestartCount != oldContainer.RestartCount {
+			reasons = append(reasons, fmt.Sprintf("Container %s restart count changed to %d", name, container.RestartCount))
+			if probeReason := pm.probeFailureReason(uid, container.Name, "liveness"); probeReason != "" {
+				reasons = append(reasons, probeReason)
+			}
+		}
+		if waiting := waitingReason(container); waiting != waitingReason(oldContainer) {
+			switch waiting {
+			case "CrashLoopBackOff":
+				reasons = append(reasons, fmt.Sprintf("Container %s is in CrashLoopBackOff (restarts=%d)", name, container.RestartCount))
+			case "", "ContainerCreating", "PodInitializing", "ImagePullBackOff", "ErrImagePull":
+				// Routine, or reported by imagePullReasons.
+			default:
+				reasons = append(reasons, fmt.Sprintf("Container %s waiting: %s", name, waiting))
+			}
+		}
+		if terminated := newlyOOMKilled(oldContainer, container); terminated != nil {
+			reasons = append(reasons, fmt.Sprintf("Container %s OOMKilled (exit code %d)", name, terminated.ExitCode))
+		}
+	}
+	return reasons
+}
+
+// imageChangeReasons reports container image changes between two pod specs,
+// matching containers by name so that reordering is not reported as a change.
+func imageChangeReasons(oldContainers, newContainers []corev1.Container) []string {
+	oldImages := make(map[string]string, len(oldContainers))
+	for _, container := range oldContainers {
+		oldImages[container.Name] = container.Image
+	}
+
+	var reasons []string
+	for _, container := range newContainers {
+		oldImage, existed := oldImages[container.Name]
+		switch {
+		case !existed:
+			reasons = append(reasons, fmt.Sprintf("Container %s added with image %s", container.Name, container.Image))
+		case oldImage != container.Image:
+			reasons = append(reasons, fmt.Sprintf("Container %s image changed from %s to %s", container.Name, oldImage, container.Image))
+		}
+		delete(oldImages, container.Name)
+	}
+
+	removed := make([]string, 0, len(oldImages))
+	for name := range oldImages {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		reasons = append(reasons, fmt.Sprintf("Container %s removed", name))
+	}
+	return reasons
+}
+
+// imagePullReasons reports containers that started failing to pull their
+// image, matching old and new statuses by container name. kind prefixes the
+// reason, e.g. "Init container".
+func imagePullReasons(oldStatuses, newStatuses []corev1.ContainerStatus, kind string) []string {
+	oldReasons := make(map[string]string, len(oldStatuses))
+	for _, status := range oldStatuses {
+		oldReasons[status.Name] = waitingReason(status)
+	}
+
+	var reasons []string
+	for _, status := range newStatuses {
+		reason := waitingReason(status)
+		if reason != "ImagePullBackOff" && reason != "ErrImagePull" {
+			continue
+		}
+		if oldReasons[status.Name] == reason {
+			continue
+		}
+
+		message := fmt.Sprintf("%s %s %s pulling image %s", kind, status.Name, reason, status.Image)
+		if status.State.Waiting.Message != "" {
+			message += ": " + status.State.Waiting.Message
+		}
+		reasons = append(reasons, message)
+	}
+	return reasons
+}
+
+func (w *podWatcher) trackedPod(uid types.UID) (*corev1.Pod, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	pod, exists := w.existingPods[string(uid)]
+	return pod, exists
+}
+
+func (w *podWatcher) observeResourceVersion(resourceVersion string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.resourceVersion = resourceVersion
+}
+
+// trackNewPod starts tracking a pod and reports whether it was not tracked
+// before, i.e. whether it still needs announcing.
+func (w *podWatcher) trackNewPod(pod *corev1.Pod) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, exists := w.existingPods[string(pod.UID)]; exists {
+		return false
+	}
+	podsWatched.Inc()
+	w.recordPhase(pod, time.Now())
+	w.existingPods[string(pod.UID)] = pod.DeepCopy()
+	return true
+}
+
+// firstSeenEvent builds the ADDED event announcing a pod. A pod is announced
+// once, whether it is first observed through ADDED, through MODIFIED (which
+// happens when an ADDED is lost or reordered across a reconnect) or in a
+// relist.
+func (w *podWatcher) firstSeenEvent(pod *corev1.Pod, message string) PodEvent {
+	podEvent := w.pm.newPodEvent(string(watch.Added), pod)
+	podEvent.Message = message
+	podEvent.Reason = w.rescheduleReason(pod, time.Now())
+	podEvent.FirstSeen = true
+	return podEvent
+}
+
+func (w *podWatcher) trackPod(pod *corev1.Pod) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, exists := w.existingPods[string(pod.UID)]; !exists {
+		podsWatched.Inc()
+	}
+	w.recordPhase(pod, time.Now())
+	w.existingPods[string(pod.UID)] = pod.DeepCopy()
+}
+
+func (w *podWatcher) untrackPod(uid types.UID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, exists := w.existingPods[string(uid)]; exists {
+		podsWatched.Dec()
+	}
+	delete(w.existingPods, string(uid))
+	delete(w.phaseSince, string(uid))
+	delete(w.lingerReported, string(uid))
+	delete(w.pendingReported, string(uid))
+	delete(w.terminatingReported, string(uid))
+	delete(w.flaps, string(uid))
+	delete(w.lastModified, string(uid))
+	delete(w.startupReported, string(uid))
+	delete(w.kubeEvents, string(uid))
+	delete(w.probeFailures, string(uid))
+}
+
+// replaceTrackedPods swaps the tracked pod set for a fresh list, keeping the
+// phase entry times of pods whose phase did not change in between. It returns
+// the previously tracked pods that are missing from the list, i.e. pods whose
+// deletion we never observed, the tracked pods whose resourceVersion moved on
+// in between, and, on relists after the first, the pods created in between.
+func (w *podWatcher) replaceTrackedPods(pods []corev1.Pod) ([]*corev1.Pod, []missedUpdate, []*corev1.Pod) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	existingPods := make(map[string]*corev1.Pod, len(pods))
+	phaseSince := make(map[string]time.Time, len(pods))
+	var updated []missedUpdate
+	var added []*corev1.Pod
+	for i := range pods {
+		uid := string(pods[i].UID)
+		oldPod, exists := w.existingPods[uid]
+		if exists && oldPod.ResourceVersion != pods[i].ResourceVersion {
+			updated = append(updated, missedUpdate{oldPod: oldPod, pod: &pods[i]})
+		}
+		if !exists && w.listed && !w.rescoped {
+			added = append(added, &pods[i])
+		}
+		if exists && oldPod.Status.Phase == pods[i].Status.Phase {
+			phaseSince[uid] = w.phaseSince[uid]
+		} else {
+			phaseSince[uid] = phaseEntryTime(&pods[i], now)
+		}
+		// Create a copy to avoid pointer issues
+		existingPods[uid] = pods[i].DeepCopy()
+	}
+
+	var stale []*corev1.Pod
+	for uid, pod := range w.existingPods {
+		if _, exists := existingPods[uid]; !exists && !w.rescoped {
+			stale = append(stale, pod)
+		}
+	}
+
+	podsWatched.Add(float64(len(existingPods) - len(w.existingPods)))
+	w.existingPods = existingPods
+	w.phaseSince = phaseSince
+	w.listed = true
+	w.rescoped = false
+	for uid := range w.lingerReported {
+		if _, exists := existingPods[uid]; !exists {
+			delete(w.lingerReported, uid)
+		}
+	}
+	for uid := range w.pendingReported {
+		if _, exists := existingPods[uid]; !exists {
+			delete(w.pendingReported, uid)
+		}
+	}
+	for uid := range w.terminatingReported {
+		if _, exists := existingPods[uid]; !exists {
+			delete(w.terminatingReported, uid)
+		}
+	}
+	for uid := range w.flaps {
+		if _, exists := existingPods[uid]; !exists {
+			delete(w.flaps, uid)
+		}
+	}
+	for uid := range w.lastModified {
+		if _, exists := existingPods[uid]; !exists {
+			delete(w.lastModified, uid)
+		}
+	}
+	for uid := range w.startupReported {
+		if _, exists := existingPods[uid]; !exists {
+			delete(w.startupReported, uid)
+		}
+	}
+	for uid := range w.probeFailures {
+		if _, exists := existingPods[uid]; !exists {
+			delete(w.probeFailures, uid)
+		}
+	}
+	for uid := range w.kubeEvents {
+		if _, exists := existingPods[uid]; !exists {
+			delete(w.kubeEvents, uid)
+		}
+	}
+	return stale, updated, added
+}
+
+// emitMissedAdditions announces the pods a relist found that were created
+// while the watch was down, so they are not tracked silently.
+func (w *podWatcher) emitMissedAdditions(ctx context.Context, added []*corev1.Pod) {
+	for _, pod := range added {
+		podEvent := w.firstSeenEvent(pod, "New pod created (missed while disconnected)")
+		podEvent.Synthetic = true
+		w.pm.logEventContext(ctx, podEvent)
+	}
+
+	if len(added) > 0 {
+		w.pm.logger.Printf("🔁 Reported %d pods created while disconnected", len(added))
+	}
+}
+
+// emitMissedDeletions emits a synthetic DELETED event for each tracked pod that
+// disappeared from a relist without a delete event, e.g. because it was
+// deleted while the watch was down or its namespace was recreated.
+func (w *podWatcher) emitMissedDeletions(ctx context.Context, stale []*corev1.Pod, current []corev1.Pod) {
+	pm := w.pm
+
+	if len(stale) == 0 {
+		return
+	}
+
+	replacements := make(map[string]types.UID, len(current))
+	for i := range current {
+		replacements[current[i].Namespace+"/"+current[i].Name] = current[i].UID
+	}
+
+	for _, pod := range stale {
+		podEvent := pm.newPodEvent(string(watch.Deleted), pod)
+		podEvent.Message = "Pod deleted (missed while disconnected)"
+		podEvent.Synthetic = true
+		if uid, replaced := replacements[pod.Namespace+"/"+pod.Name]; replaced {
+			podEvent.Reason = fmt.Sprintf("Replaced by a new pod with the same name (UID %s)", uid)
+		}
+		pm.logEventContext(ctx, podEvent)
+	}
+
+	pm.logger.Printf("🧹 Evicted %d stale pods that disappeared without a delete event", len(stale))
+}
+
+// missedUpdate is a tracked pod that changed while the watch was down.
+type missedUpdate struct {
+	oldPod *corev1.Pod
+	pod    *corev1.Pod
+}
+
+// emitMissedUpdates emits a synthetic MODIFIED event for each pod that
+// changed while the watch was down. Pods whose resourceVersion moved without
+// any change getChangeReason recognises, e.g. metadata-only updates, are
+// skipped.
+func (w *podWatcher) emitMissedUpdates(ctx context.Context, updated []missedUpdate) {
+	pm := w.pm
+
+	emitted := 0
+	for _, update := range updated {
+		reason := pm.getChangeReason(update.oldPod, update.pod)
+		if reason == genericChangeReason {
+			continue
+		}
+
+		podEvent := pm.newPodEvent(string(watch.Modified), update.pod)
+		podEvent.Message = "Pod updated (missed while disconnected)"
+		podEvent.Reason = reason
+		podEvent.Synthetic = true
+		if inCrashLoop(update.pod) || wasOOMKilled(update.oldPod, update.pod) || unschedulableReason(update.oldPod, update.pod) != "" {
+			podEvent.Severity = severityWarning
+		}
+		pm.logEventContext(ctx, podEvent)
+		for _, alert := range pm.restartThresholdEvents(update.oldPod, update.pod) {
+			pm.logEventContext(ctx, alert)
+		}
+		emitted++
+	}
+
+	if emitted > 0 {
+		pm.logger.Printf("🔁 Reported %d pods that changed while disconnected", emitted)
+	}
+}
+
+// errWatchExpired is returned by resourceWatch.consume when the API server no
+// longer has the requested resourceVersion and the objects must be listed
+// again.
+var errWatchExpired = errors.New("watch resource version expired")
+
+// errStopped signals that stopCh was closed while watching or backing off.
+var errStopped = errors.New("pod monitor stopped")
+
+// watchPods lists the pods once and then keeps a watch open, resuming each
+// reconnect from the last observed resourceVersion. The pods are only listed
+// again when that resourceVersion has expired.
+func (w *podWatcher) watchPods(ctx context.Context) error {
+	if w.pm.watchMode == watchModeInformer {
+		return w.runInformer(ctx)
+	}
+
+	if err := w.watchLoop(ctx); !errors.Is(err, errStopped) {
+		return err
+	}
+	return nil
+}
+
+func (w *podWatcher) watchLoop(ctx context.Context) error {
+	pm := w.pm
+
+	r := &resourceWatch{
+		logger: pm.logger,
+		list:   w.listPods,
+		watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return pm.clientset.CoreV1().Pods(w.namespace).Watch(ctx, metav1.ListOptions{
+				FieldSelector:       pm.fieldSelector,
+				ResourceVersion:     resourceVersion,
+				AllowWatchBookmarks: true,
+			})
+		},
+		handle: func(eventType watch.EventType, obj runtime.Object) bool {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				return false
+			}
+			w.observeResourceVersion(pod.ResourceVersion)
+			if eventType != watch.Bookmark && pm.inScope(pod) {
+				w.handlePodEvent(eventType, pod)
+			}
+			return true
+		},
+		backoff: w.backoff,
+
+		// Reset retry count on successful event
+		onEvent:     func() { w.retryCount = 0 },
+		onWatching:  w.setWatching,
+		onReconnect: watchReconnectsTotal.Inc,
+		stopCh:      pm.stopCh,
+
+		relistInterval: pm.relistInterval,
+
+		messages: watchMessages{
+			listFailed:  "❌ Failed to list pods in namespace " + w.label() + ": %v",
+			watchFailed: "❌ Failed to create pod watcher for namespace " + w.label() + ": %v",
+			expired:     "⚠️  Watch resource version expired for namespace " + w.label() + ", relisting pods",
+			watchError:  "❌ Watch error: %v",
+			unexpected:  "⚠️  Unexpected object type: %T",
+			cancelled:   "🛑 Context cancelled, stopping pod monitor",
+			stopped:     "🛑 Stop signal received, stopping pod monitor",
+		},
+	}
+	return r.run(ctx)
+}
+
+// listPods replaces the tracked pods with a fresh list, reports pods that
+// disappeared in the meantime and returns the list's resourceVersion.
+func (w *podWatcher) listPods(ctx context.Context) (string, error) {
+	pm := w.pm
+
+	// A relist after a reconnect belongs to the reconnect's trace.
+	spanCtx := ctx
+	if w.reconnect.IsValid() {
+		spanCtx = trace.ContextWithSpanContext(ctx, w.reconnect)
+	}
+	spanCtx, span := pm.startSpan(spanCtx, "list pods", "namespace", w.label())
+	defer span.End()
+
+	// Only the list is bounded; the watch that follows runs on ctx.
+	listCtx, cancel := context.WithTimeout(spanCtx, pm.listTimeout)
+	defer cancel()
+
+	pods, err := pm.clientset.CoreV1().Pods(w.namespace).List(listCtx, metav1.ListOptions{
+		FieldSelector: pm.fieldSelector,
+	})
+	if err != nil {
+		endSpan(span, err)
+		if ctx.Err() == nil && errors.Is(listCtx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("listing pods timed out after %v (raise --list-timeout on slow API servers)", pm.listTimeout)
+		}
+		return "", err
+	}
+
+	inScope := pods.Items[:0]
+	for i := range pods.Items {
+		if pm.inScope(&pods.Items[i]) {
+			inScope = append(inScope, pods.Items[i])
+		}
+	}
+	pods.Items = inScope
+
+	w.observeResourceVersion(pods.ResourceVersion)
+	stale, updated, added := w.replaceTrackedPods(pods.Items)
+	w.emitMissedDeletions(spanCtx, stale, pods.Items)
+	w.emitMissedAdditions(spanCtx, added)
+	w.emitMissedUpdates(spanCtx, updated)
+
+	pm.logger.Printf("🚀 Starting pod monitor for namespace: %s (found %d existing pods)", w.label(), len(pods.Items))
+	return pods.ResourceVersion, nil
+}
+
+// setWatching records whether the watch is open. A watch that stayed open
+// for stablePeriod starts its failure count over, whether or not events
+// arrived, so an idle namespace does not carry old failures into the next
+// blip.
+func (w *podWatcher) setWatching(watching bool) {
+	w.ready.Store(watching)
+	if watching {
+		w.watchingSince = w.pm.now()
+		w.reconnect = trace.SpanContext{}
+		return
+	}
+	if w.pm.stablePeriod > 0 && w.pm.now().Sub(w.watchingSince) >= w.pm.stablePeriod {
+		w.retryCount = 0
+	}
+}
+
+// backoff waits before the next reconnect attempt, growing exponentially with
+// the number of consecutive failures. It returns an error once maxRetries is
+// reached, unless retrying forever, or when the monitor is stopped.
+func (w *podWatcher) backoff(ctx context.Context) error {
+	pm := w.pm
+
+	w.retryCount++
+	if !pm.retryForever && w.retryCount >= pm.maxRetries {
+		return fmt.Errorf("watch failed after %d retries", pm.maxRetries)
+	}
+
+	backoffDuration := pm.backoff.delay(w.retryCount)
+	if pm.retryForever {
+		pm.logger.Printf("⚠️  Watch for namespace %s interrupted, retrying in %v (attempt %d)",
+			w.label(), backoffDuration, w.retryCount)
+	} else {
+		pm.logger.Printf("⚠️  Watch for namespace %s interrupted, retrying in %v (attempt %d/%d)",
+			w.label(), backoffDuration, w.retryCount, pm.maxRetries)
+	}
+	_, span := pm.startSpan(ctx, "reconnect backoff", "namespace", w.label(), "attempt", strconv.Itoa(w.retryCount))
+	defer span.End()
+	w.reconnect = span.SpanContext()
+
+	select {
+	case <-time.After(backoffDuration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-pm.stopCh:
+		return errStopped
+	}
+}
+
+// handlePodEvent emits the event for one pod change and updates the tracked
+// state. Both the raw watch and the informer mode feed it.
+func (w *podWatcher) handlePodEvent(eventType watch.EventType, pod *corev1.Pod) {
+	pm := w.pm
+
+	start := time.Now()
+	defer func() {
+		eventProcessingSeconds.Observe(time.Since(start).Seconds())
+	}()
+	if eventType != watch.Deleted {
+		w.observeLag(pod, start)
+	}
+
+	// Each watch event starts a trace; the events it emits are its children.
+	ctx, span := pm.startSpan(context.Background(), "handle pod event", "namespace", pod.Namespace, "event_type", string(eventType))
+	defer span.End()
+
+	podEvent := pm.newPodEvent(string(eventType), pod)
+
+	switch eventType {
+	case watch.Added:
+		if w.trackNewPod(pod) {
+			pm.logEventContext(ctx, w.firstSeenEvent(pod, "New pod created"))
+		}
+
+	case watch.Deleted:
+		podEvent.Message = "Pod deleted"
+		podEvent.KubeEvent = w.takeKubeEvent(pod.UID)
+		if podEvent.KubeEvent != nil && podEvent.KubeEvent.Reason == "Preempted" {
+			podEvent.Reason = "Pod preempted: " + podEvent.KubeEvent.Message
+			podEvent.Severity = severityWarning
+		}
+		podEvent.ContainerResults = containerResults(pod)
+		if lifetime, ok := pm.podLifetime(pod, time.Now()); ok {
+			podEvent.LifetimeSeconds = lifetime.Seconds()
+			podLifetimeSeconds.Observe(lifetime.Seconds())
+		}
+		pm.logEventContext(ctx, podEvent)
+		w.untrackPod(pod.UID)
+		w.rememberPlacement(pod, time.Now())
+
+	case watch.Modified:
+		if oldPod, exists := w.trackedPod(pod.UID); exists {
+			// While a flapping pod cools down, its restarts are left out of
+			// the reason and an update with nothing else is suppressed.
+			flapEvent, coolingDown := w.recordRestarts(oldPod, pod, time.Now())
+			changed := pod
+			if coolingDown && !podEvent.Important {
+				changed = withoutRestarts(oldPod, pod)
+			}
+			reason := pm.getChangeReason(oldPod, changed)
+			suppressed := changed != pod && reason == genericChangeReason
+			if oldPod.Spec.NodeName == "" {
+				if rescheduled := w.rescheduleReason(pod, time.Now()); rescheduled != "" {
+					reason += "; " + rescheduled
+				}
+			}
+			podEvent.Reason = reason
+			if inCrashLoop(pod) || wasOOMKilled(oldPod, pod) || unschedulableReason(oldPod, pod) != "" {
+				podEvent.Severity = severityWarning
+			}
+			if oldPod.Status.Phase != pod.Status.Phase {
+				podEvent.ContainerResults = containerResults(pod)
+				if inPhase, ok := w.timeInPhase(pod.UID); ok {
+					podEvent.PhaseDurationSeconds = inPhase.Seconds()
+					podPhaseDurationSeconds.WithLabelValues(string(oldPod.Status.Phase)).Observe(inPhase.Seconds())
+				}
+			}
+			if startup, ok := startupLatency(oldPod, pod); ok && w.firstReady(string(pod.UID)) {
+				podEvent.StartupSeconds = startup.Seconds()
+				podStartupSeconds.Observe(startup.Seconds())
+			}
+			podEvent.Message = "Pod updated"
+			if pm.services != nil {
+				services, note := pm.services.readinessChange(oldPod, pod)
+				podEvent.Services = services
+				if note != "" {
+					podEvent.Reason += "; " + note
+					// Leaving the endpoints while not being deleted means lost
+					// serving capacity.
+					if podReady(pod) == nil && pod.DeletionTimestamp == nil {
+						podEvent.Severity = severityWarning
+					}
+				}
+			}
+
+			for _, alert := range pm.restartThresholdEvents(oldPod, pod) {
+				pm.logEventContext(ctx, alert)
+			}
+			if flapEvent != nil {
+				pm.logEventContext(ctx, *flapEvent)
+			}
+			if !suppressed && !w.throttled(podEvent, string(pod.UID), time.Now()) {
+				podEvent.KubeEvent = w.takeKubeEvent(pod.UID)
+				pm.logEventContext(ctx, podEvent)
+			}
+			w.trackPod(pod)
+		} else if w.trackNewPod(pod) {
+			// The pod's ADDED was missed or has not arrived yet; announce it
+			// now and ignore the ADDED if it comes later.
+			pm.logEventContext(ctx, w.firstSeenEvent(pod, "New pod detected during watch"))
+		}
+	}
+}
+
+func (pm *PodMonitor) Start() error {
+	defer pm.closeEvents()
+
+	// With --duration the root context expires on its own and everything
+	// stops the same way as on a shutdown signal.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if pm.runDuration > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, pm.runDuration)
+		defer cancelTimeout()
+	}
+
+	// Handle graceful shutdown. Once Start returns, for example when
+	// --duration expires, the signals are released and the goroutine exits.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	go func() {
+		select {
+		case <-sigCh:
+			pm.logger.Println("📶 Received shutdown signal")
+			close(pm.stopCh)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// SIGHUP re-reads the --config file.
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	go func() {
+		for {
+			select {
+			case <-hupCh:
+				pm.reload()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Test connectivity
+	_, err := pm.clientset.CoreV1().Namespaces().Get(ctx, "default", metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to connect to Kubernetes API: %v", explainImpersonation(err, pm.impersonate))
+	}
+
+	pm.logger.Println("✅ Successfully connected to Kubernetes API")
+	if pm.impersonate != "" {
+		pm.logger.Printf("🎭 Impersonating %s for API requests", pm.impersonate)
+	}
+	pm.connected.Store(true)
+
+	if err := pm.checkPodAccess(ctx); err != nil {
+		if pm.strictRBAC {
+			return err
+		}
+		pm.logger.Printf("⚠️  %v, the pod watches will fail until it is granted", err)
+	}
+
+	if len(pm.watchedNamespaces()) == 0 {
+		pm.logger.Println("🌐 Cluster-wide mode: watching pods in all namespaces (requires cluster-scoped pod list/watch)")
+		go pm.reportTrackedPodTotal(ctx)
+	}
+
+	if pm.otel != nil {
+		defer pm.otel.Close(5 * time.Second)
+	}
+
+	for _, sink := range pm.asyncSinks {
+		go sink.run()
+	}
+	defer pm.flushSinks(pm.shutdownTimeout)
+
+	pm.startHTTPServers(ctx)
+
+	// The lifecycle events bracket the whole run, not each watch scope a
+	// reload switches to.
+	watch := func(ctx context.Context) error {
+		pm.emitLifecycleEvent("MONITOR_STARTED", "Pod monitor started")
+		err := pm.runWatches(ctx)
+		if err == nil {
+			pm.emitLifecycleEvent("MONITOR_STOPPED", "Pod monitor stopped")
+		}
+		return err
+	}
+	if pm.leaderElection != nil {
+		err = pm.runAsLeader(ctx, watch)
+	} else {
+		err = watch(ctx)
+	}
+	if err == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		pm.logger.Printf("⏱️  Run duration of %v elapsed: %d events emitted, %s",
+			pm.runDuration, pm.eventsEmitted.Load(), pm.healthSummary().Message)
+	}
+	return err
+}
+
+// run starts the watchers and background reporters and blocks until the
+// watchers stop. With leader election it only runs on the leader, and ctx is
+// cancelled when leadership is lost.
+func (pm *PodMonitor) run(ctx context.Context) error {
+	if pm.watchEvents {
+		for _, w := range pm.podWatchers() {
+			go pm.watchProbeEvents(ctx, w.namespace)
+		}
+	}
+
+	if pm.correlateEvents {
+		for _, w := range pm.podWatchers() {
+			go w.correlateKubeEvents(ctx)
+		}
+	}
+
+	if pm.nodeWatcher != nil {
+		go pm.nodeWatcher.run(ctx)
+	}
+
+	for _, w := range pm.workloadWatchers {
+		go w.run(ctx)
+	}
+
+	for _, w := range pm.pvcWatchers {
+		go w.run(ctx)
+	}
+
+	if pm.podCountInterval > 0 {
+		go pm.reportPodCounts(ctx)
+	}
+
+	if pm.resyncPeriod > 0 {
+		go pm.reportResync(ctx)
+	}
+
+	if pm.summaryInterval > 0 {
+		go pm.reportHealthSummary(ctx)
+	}
+
+	if pm.sinkHeartbeatInterval > 0 {
+		go pm.sendSinkHeartbeats(ctx)
+	}
+
+	if pm.terminalLingerThreshold > 0 {
+		go pm.watchTerminalLinger(ctx)
+	}
+
+	if pm.pendingThreshold > 0 {
+		go pm.watchStuckPending(ctx)
+	}
+
+	go pm.watchStuckTerminating(ctx)
+
+	if pm.metricsClient != nil {
+		go pm.reportUsage(ctx)
+	}
+
+	if pm.nodeLabels != nil {
+		go pm.nodeLabels.run(ctx)
+	}
+
+	if pm.services != nil {
+		go pm.services.run(ctx)
+	}
+
+	// Each watcher runs until shutdown or until it gives up; a watcher that
+	// gives up does not stop the others.
+	watchers := pm.podWatchers()
+	errs := make([]error, len(watchers))
+	var wg sync.WaitGroup
+	for i, w := range watchers {
+		wg.Add(1)
+		go func(i int, w *podWatcher) {
+			defer wg.Done()
+			if err := w.watchPods(ctx); err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, ctx.Err()) {
+				pm.logger.Printf("❌ Stopped watching namespace %s: %v", w.label(), err)
+				errs[i] = fmt.Errorf("namespace %s: %v", w.label(), err)
+			}
+		}(i, w)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// healthCheckTimeout bounds the --health-check request.
+const healthCheckTimeout = 8 * time.Second
+
+// HealthCheck checks that cfg's client can reach the Kubernetes API, exiting
+// with status 0 or 1. It backs --health-check.
+func HealthCheck(cfg Config) {
+	if err := checkHealth(cfg); err != nil {
+		log.Printf("Health check failed: %v", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Health check passed: pod monitor is healthy")
+	os.Exit(0)
+}
+
+// checkHealth asks the API server for its version. Only the client is built,
+// so a health check probe does not open the event database or output file or
+// dial any sink.
+func checkHealth(cfg Config) error {
+	config, err := kubeClientConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to create Kubernetes client: %v", err)
+	}
+	// Allow more time than a probe's default for slow networks.
+	config.Timeout = healthCheckTimeout
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("unable to create Kubernetes client: %v", err)
+	}
+	if _, err := clientset.Discovery().ServerVersion(); err != nil {
+		return fmt.Errorf("unable to connect to Kubernetes API: %v", explainImpersonation(err, config.Impersonate.UserName))
+	}
+	return nil
+}
@@ -0,0 +1,734 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newTestMonitor builds a monitor around a fake clientset holding objects,
+// watching namespace, with its log output discarded and events only
+// delivered through Events().
+func newTestMonitor(t *testing.T, namespace string, objects ...runtime.Object) (*PodMonitor, *fake.Clientset) {
+	t.Helper()
+	t.Setenv("LOG_EVENTS", "false")
+
+	client := fake.NewSimpleClientset(objects...)
+	pm, err := NewPodMonitorWithClient(client, namespace)
+	if err != nil {
+		t.Fatalf("NewPodMonitorWithClient: %v", err)
+	}
+	pm.logger.SetOutput(io.Discard)
+	return pm, client
+}
+
+// startWatching runs the monitor's watches until the test ends and returns
+// its event stream once every pod watch is open.
+func startWatching(t *testing.T, pm *PodMonitor) <-chan PodEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := pm.Events(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- pm.run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	waitFor(t, "the pod watches to open", func() bool {
+		for _, w := range pm.podWatchers() {
+			if !w.ready.Load() {
+				return false
+			}
+		}
+		return true
+	})
+	return events
+}
+
+// waitFor polls condition until it holds, failing the test after 5s.
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// nextEvent returns the next event of eventType, skipping other types.
+func nextEvent(t *testing.T, events <-chan PodEvent, eventType string) PodEvent {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("event stream closed waiting for %s", eventType)
+			}
+			if event.EventType == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for a %s event", eventType)
+		}
+	}
+}
+
+// expectNoEvent fails if an event of eventType arrives within wait.
+func expectNoEvent(t *testing.T, events <-chan PodEvent, eventType string, wait time.Duration) {
+	t.Helper()
+	timeout := time.After(wait)
+	for {
+		select {
+		case event := <-events:
+			if event.EventType == eventType {
+				t.Fatalf("unexpected %s event: %+v", eventType, event)
+			}
+		case <-timeout:
+			return
+		}
+	}
+}
+
+// testPod returns a Pending pod. The fake clientset does not assign UIDs, so
+// the pod's UID is derived from its name.
+func testPod(namespace, name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID(name + "-uid"),
+			Labels:    map[string]string{"app": name},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx:1.25"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
+
+func TestWatchReportsPodLifecycle(t *testing.T) {
+	pm, client := newTestMonitor(t, "default")
+	events := startWatching(t, pm)
+	ctx := context.Background()
+	pods := client.CoreV1().Pods("default")
+
+	pod := testPod("default", "web")
+	if _, err := pods.Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	added := nextEvent(t, events, "ADDED")
+	if added.PodName != "web" || added.Namespace != "default" || !added.FirstSeen {
+		t.Errorf("ADDED event = %+v, want first-seen default/web", added)
+	}
+	if added.Phase != "Pending" {
+		t.Errorf("ADDED phase = %q, want Pending", added.Phase)
+	}
+
+	pod.Status.Phase = corev1.PodRunning
+	pod.Spec.NodeName = "node-1"
+	if _, err := pods.Update(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	modified := nextEvent(t, events, "MODIFIED")
+	if !strings.Contains(modified.Reason, "Phase changed from Pending to Running") {
+		t.Errorf("MODIFIED reason = %q, want the phase change", modified.Reason)
+	}
+	if !strings.Contains(modified.Reason, "Pod scheduled to node node-1") {
+		t.Errorf("MODIFIED reason = %q, want the node assignment", modified.Reason)
+	}
+	if modified.NodeName != "node-1" {
+		t.Errorf("MODIFIED node = %q, want node-1", modified.NodeName)
+	}
+
+	if err := pods.Delete(ctx, "web", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	deleted := nextEvent(t, events, "DELETED")
+	if deleted.PodName != "web" || deleted.Synthetic {
+		t.Errorf("DELETED event = %+v, want a watched deletion of web", deleted)
+	}
+
+	waitFor(t, "the pod to be untracked", func() bool {
+		_, tracked := pm.trackedPod(pod.UID)
+		return !tracked
+	})
+}
+
+func TestWatchTracksExistingPodsSilently(t *testing.T) {
+	pm, client := newTestMonitor(t, "default", testPod("default", "existing"))
+	events := startWatching(t, pm)
+
+	if _, tracked := pm.trackedPod("existing-uid"); !tracked {
+		t.Fatal("pod present at startup is not tracked")
+	}
+
+	if _, err := client.CoreV1().Pods("default").Create(context.Background(), testPod("default", "new"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if added := nextEvent(t, events, "ADDED"); added.PodName != "new" {
+		t.Errorf("first ADDED is for %s, want only the new pod announced", added.PodName)
+	}
+}
+
+func TestWatchIgnoresOtherNamespaces(t *testing.T) {
+	pm, client := newTestMonitor(t, "default")
+	events := startWatching(t, pm)
+
+	if _, err := client.CoreV1().Pods("other").Create(context.Background(), testPod("other", "elsewhere"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expectNoEvent(t, events, "ADDED", 200*time.Millisecond)
+}
+
+func TestFieldSelectorRestrictsListAndWatch(t *testing.T) {
+	t.Setenv("FIELD_SELECTOR", "spec.nodeName=node-1")
+	onNode := testPod("default", "on-node")
+	onNode.Spec.NodeName = "node-1"
+	elsewhere := testPod("default", "elsewhere")
+	elsewhere.Spec.NodeName = "node-2"
+	pm, client := newTestMonitor(t, "default", onNode, elsewhere)
+
+	// The fake clientset ignores field selectors, so record them and apply
+	// spec.nodeName to lists like the API server would.
+	var mu sync.Mutex
+	var selectors []string
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.ListActionImpl).GetListRestrictions().Fields
+		mu.Lock()
+		selectors = append(selectors, "list "+selector.String())
+		mu.Unlock()
+
+		list := &corev1.PodList{}
+		for _, pod := range []*corev1.Pod{onNode, elsewhere} {
+			if selector.Matches(fields.Set{"spec.nodeName": pod.Spec.NodeName}) {
+				list.Items = append(list.Items, *pod)
+			}
+		}
+		return true, list, nil
+	})
+	client.PrependWatchReactor("pods", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		mu.Lock()
+		selectors = append(selectors, "watch "+action.(k8stesting.WatchActionImpl).GetWatchRestrictions().Fields.String())
+		mu.Unlock()
+		return false, nil, nil
+	})
+	startWatching(t, pm)
+
+	if _, tracked := pm.trackedPod("on-node-uid"); !tracked {
+		t.Error("pod on node-1 is not tracked")
+	}
+	if _, tracked := pm.trackedPod("elsewhere-uid"); tracked {
+		t.Error("pod on node-2 is tracked despite the field selector")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"list spec.nodeName=node-1", "watch spec.nodeName=node-1"}
+	if len(selectors) < 2 || selectors[0] != want[0] || selectors[1] != want[1] {
+		t.Errorf("requests = %q, want %q", selectors, want)
+	}
+}
+
+func TestDefaultConfigReportsInvalidConfigFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", t.TempDir()+"/missing.yaml")
+	if _, err := DefaultConfig(); err == nil {
+		t.Fatal("DefaultConfig with a missing CONFIG_FILE returned no error")
+	}
+	if _, err := NewPodMonitorWithClient(fake.NewSimpleClientset(), "default"); err == nil {
+		t.Fatal("NewPodMonitorWithClient with a missing CONFIG_FILE returned no error")
+	}
+}
+
+func TestConfigFileSetsMonitorSettings(t *testing.T) {
+	t.Setenv("WATCH_STRATEGY", "server_side")
+	t.Setenv("LOG_EVENTS", "true")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := `watch-strategy: client_side
+important-label: tier=critical
+summary-interval: 15m
+log-events: false
+`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+
+	pm, err := NewPodMonitorWithClient(fake.NewSimpleClientset(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pm.watchStrategy != watchStrategyClientSide {
+		t.Errorf("watch strategy = %q, want the file's %q over the environment", pm.watchStrategy, watchStrategyClientSide)
+	}
+	if pm.importantLabelKey != "tier" || pm.importantLabelValue != "critical" {
+		t.Errorf("important label = %s=%s, want tier=critical", pm.importantLabelKey, pm.importantLabelValue)
+	}
+	if pm.summaryInterval != 15*time.Minute {
+		t.Errorf("summary interval = %v, want 15m", pm.summaryInterval)
+	}
+	if pm.logEvents {
+		t.Error("log-events: false in the config file left stdout output on")
+	}
+}
+
+func TestParseFlagsReportsUnknownFlag(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	if _, _, err := parseConfig([]string{"--no-such-flag"}, io.Discard); err == nil {
+		t.Fatal("unknown flag returned no error")
+	}
+}
+
+// writeKubeconfig writes a kubeconfig pointing at server and returns its path.
+func writeKubeconfig(t *testing.T, server string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+current-context: test
+`, server)
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHealthCheckOnlyQueriesServerVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "reachable", status: http.StatusOK},
+		{name: "failing", status: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"major": "1", "minor": "30", "gitVersion": "v1.30.0"}`)
+			}))
+			defer server.Close()
+
+			t.Setenv("CONFIG_FILE", "")
+			cfg, err := DefaultConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Kubeconfig = writeKubeconfig(t, server.URL)
+			cfg.OutputFile = filepath.Join(t.TempDir(), "events.jsonl")
+
+			err = checkHealth(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkHealth error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(paths) == 0 {
+				t.Fatal("health check made no request")
+			}
+			for _, path := range paths {
+				if path != "/version" {
+					t.Errorf("health check requested %s, want only /version", path)
+				}
+			}
+			if _, err := os.Stat(cfg.OutputFile); !os.IsNotExist(err) {
+				t.Error("health check created the output file")
+			}
+		})
+	}
+}
+
+func TestPodWatchErrorEventKeepsRetryCount(t *testing.T) {
+	pm, client := newTestMonitor(t, "default")
+	podWatch := watch.NewFake()
+	client.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		return true, podWatch, nil
+	})
+	startWatching(t, pm)
+	w := pm.podWatchers()[0]
+
+	// FakeWatcher sends block until the watcher receives, so once a send
+	// returns the event before it has been handled.
+	watchError := &metav1.Status{Status: metav1.StatusFailure, Code: 500, Message: "etcd unavailable"}
+	w.retryCount = 3
+	podWatch.Error(watchError)
+	podWatch.Error(watchError)
+	if w.retryCount != 3 {
+		t.Fatalf("retry count after a watch error = %d, want it kept at 3", w.retryCount)
+	}
+
+	pod := testPod("default", "web")
+	pod.ResourceVersion = "5"
+	podWatch.Add(pod)
+	podWatch.Error(watchError)
+	if w.retryCount != 0 {
+		t.Fatalf("retry count after a pod event = %d, want 0", w.retryCount)
+	}
+}
+
+// fakeClock is a clock tests move forward by hand.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestIdleWatchResetsRetryCountAfterStablePeriod(t *testing.T) {
+	t.Setenv("STABLE_PERIOD", "1m")
+	pm, client := newTestMonitor(t, "default")
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	pm.now = clock.Now
+	opened := make(chan *watch.FakeWatcher, 1)
+	client.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		podWatch := watch.NewFake()
+		opened <- podWatch
+		return true, podWatch, nil
+	})
+	startWatching(t, pm)
+	w := pm.podWatchers()[0]
+
+	// Receiving the next watch orders the test after the watcher closed the
+	// previous one and updated its retry count.
+	reconnect := func(open time.Duration) {
+		t.Helper()
+		podWatch := <-opened
+		clock.Advance(open)
+		podWatch.Stop()
+		select {
+		case next := <-opened:
+			opened <- next
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the watch to reopen")
+		}
+	}
+
+	w.retryCount = 3
+	reconnect(30 * time.Second)
+	if w.retryCount != 3 {
+		t.Fatalf("retry count after a short idle watch = %d, want it kept at 3", w.retryCount)
+	}
+	reconnect(time.Minute)
+	if w.retryCount != 0 {
+		t.Fatalf("retry count after an idle watch stable for 1m = %d, want 0", w.retryCount)
+	}
+}
+
+func TestRelistReportsMissedDeletion(t *testing.T) {
+	t.Setenv("RELIST_INTERVAL", "100ms")
+	pm, client := newTestMonitor(t, "default", testPod("default", "web"))
+	// Watches that never deliver anything, so the deletion below is missed.
+	client.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		return true, watch.NewFake(), nil
+	})
+	events := startWatching(t, pm)
+
+	gvr := corev1.SchemeGroupVersion.WithResource("pods")
+	if err := client.Tracker().Delete(gvr, "default", "web"); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := nextEvent(t, events, "DELETED")
+	if deleted.PodName != "web" || !deleted.Synthetic {
+		t.Errorf("DELETED event = %+v, want a synthetic deletion of web", deleted)
+	}
+	if deleted.Message != "Pod deleted (missed while disconnected)" {
+		t.Errorf("DELETED message = %q", deleted.Message)
+	}
+	waitFor(t, "the pod to be untracked", func() bool {
+		_, tracked := pm.trackedPod("web-uid")
+		return !tracked
+	})
+}
+
+func TestRescopeKeepsTrackedPods(t *testing.T) {
+	pm, client := newTestMonitor(t, "default", testPod("default", "web"), testPod("default", "api"))
+	// Watches that never deliver anything, so the deletion below is missed.
+	client.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		return true, watch.NewFake(), nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := pm.Events(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- pm.runWatches(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	kept := pm.podWatchers()[0]
+	waitFor(t, "the pod watch to open", kept.ready.Load)
+
+	gvr := corev1.SchemeGroupVersion.WithResource("pods")
+	if err := client.Tracker().Delete(gvr, "default", "api"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := pm.config
+	cfg.Namespaces = []string{"default", "shop"}
+	scope, err := newWatchScope(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pm.rescope <- scope
+
+	deleted := nextEvent(t, events, "DELETED")
+	if deleted.PodName != "api" || !deleted.Synthetic {
+		t.Errorf("DELETED event = %+v, want a synthetic deletion of api", deleted)
+	}
+	watchers := pm.podWatchers()
+	if len(watchers) != 2 || watchers[0] != kept {
+		t.Fatalf("watchers after the rescope = %v, want the default watcher kept and one for shop", watchers)
+	}
+	if _, tracked := pm.trackedPod("web-uid"); !tracked {
+		t.Error("web is no longer tracked after the rescope")
+	}
+	for _, eventType := range []string{"ADDED", "MONITOR_STOPPED", "MONITOR_STARTED"} {
+		expectNoEvent(t, events, eventType, 100*time.Millisecond)
+	}
+}
+
+func TestModifiedBeforeAddedAnnouncesPodOnce(t *testing.T) {
+	pm, client := newTestMonitor(t, "default")
+	podWatch := watch.NewFake()
+	client.PrependWatchReactor("pods", func(k8stesting.Action) (bool, watch.Interface, error) {
+		return true, podWatch, nil
+	})
+	events := startWatching(t, pm)
+
+	pod := testPod("default", "web")
+	pod.ResourceVersion = "2"
+	podWatch.Modify(pod)
+	first := nextEvent(t, events, "ADDED")
+	if !first.FirstSeen || first.PodName != "web" || first.Message != "New pod detected during watch" {
+		t.Errorf("event for the early MODIFIED = %+v, want a first-seen ADDED for web", first)
+	}
+
+	late := pod.DeepCopy()
+	late.ResourceVersion = "1"
+	podWatch.Add(late)
+	expectNoEvent(t, events, "ADDED", 200*time.Millisecond)
+}
+
+func TestGetChangeReasonCrashLoopBackOff(t *testing.T) {
+	waiting := func(reason string) corev1.ContainerState {
+		return corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}
+	}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+
+	tests := []struct {
+		name            string
+		init, ephemeral bool
+		old, new        corev1.ContainerStatus
+		wantReason      string
+		wantWarning     bool
+	}{
+		{
+			name:        "enters CrashLoopBackOff",
+			old:         corev1.ContainerStatus{Name: "app", RestartCount: 3, State: running},
+			new:         corev1.ContainerStatus{Name: "app", RestartCount: 3, State: waiting("CrashLoopBackOff")},
+			wantReason:  "Container app is in CrashLoopBackOff (restarts=3)",
+			wantWarning: true,
+		},
+		{
+			name:        "stays in CrashLoopBackOff",
+			old:         corev1.ContainerStatus{Name: "app", RestartCount: 3, State: waiting("CrashLoopBackOff")},
+			new:         corev1.ContainerStatus{Name: "app", RestartCount: 4, State: waiting("CrashLoopBackOff")},
+			wantReason:  "Container app restart count changed to 4",
+			wantWarning: true,
+		},
+		{
+			name:        "init container enters CrashLoopBackOff",
+			init:        true,
+			old:         corev1.ContainerStatus{Name: "setup", RestartCount: 1, State: running},
+			new:         corev1.ContainerStatus{Name: "setup", RestartCount: 1, State: waiting("CrashLoopBackOff")},
+			wantReason:  "Container init:setup is in CrashLoopBackOff (restarts=1)",
+			wantWarning: true,
+		},
+		{
+			name:        "ephemeral container enters CrashLoopBackOff",
+			ephemeral:   true,
+			old:         corev1.ContainerStatus{Name: "debugger", RestartCount: 2, State: running},
+			new:         corev1.ContainerStatus{Name: "debugger", RestartCount: 2, State: waiting("CrashLoopBackOff")},
+			wantReason:  "Container ephemeral:debugger is in CrashLoopBackOff (restarts=2)",
+			wantWarning: true,
+		},
+		{
+			name:       "recovers",
+			old:        corev1.ContainerStatus{Name: "app", RestartCount: 4, State: waiting("CrashLoopBackOff")},
+			new:        corev1.ContainerStatus{Name: "app", RestartCount: 4, State: running},
+			wantReason: genericChangeReason,
+		},
+		{
+			name:       "other waiting reason",
+			old:        corev1.ContainerStatus{Name: "app", State: waiting("ContainerCreating")},
+			new:        corev1.ContainerStatus{Name: "app", State: waiting("CreateContainerConfigError")},
+			wantReason: "Container app waiting: CreateContainerConfigError",
+		},
+	}
+	pm, _ := newTestMonitor(t, "default")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldPod, newPod := testPod("default", "web"), testPod("default", "web")
+			switch {
+			case tt.init:
+				oldPod.Status.InitContainerStatuses = []corev1.ContainerStatus{tt.old}
+				newPod.Status.InitContainerStatuses = []corev1.ContainerStatus{tt.new}
+			case tt.ephemeral:
+				oldPod.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{tt.old}
+				newPod.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{tt.new}
+			default:
+				oldPod.Status.ContainerStatuses = []corev1.ContainerStatus{tt.old}
+				newPod.Status.ContainerStatuses = []corev1.ContainerStatus{tt.new}
+			}
+
+			if got := pm.getChangeReason(oldPod, newPod); got != tt.wantReason {
+				t.Errorf("reason = %q, want %q", got, tt.wantReason)
+			}
+			if got := inCrashLoop(newPod); got != tt.wantWarning {
+				t.Errorf("inCrashLoop = %v, want %v", got, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestWasOOMKilled(t *testing.T) {
+	oomKilledAt := func(finished time.Time) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason: "OOMKilled", ExitCode: 137, FinishedAt: metav1.NewTime(finished)}}
+	}
+	earlier := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+
+	tests := []struct {
+		name            string
+		init, ephemeral bool
+		old, new        []corev1.ContainerStatus
+		want            bool
+	}{
+		{
+			name: "newly OOMKilled",
+			old:  []corev1.ContainerStatus{{Name: "app"}},
+			new:  []corev1.ContainerStatus{{Name: "app", LastTerminationState: oomKilledAt(later)}},
+			want: true,
+		},
+		{
+			name: "already reported",
+			old:  []corev1.ContainerStatus{{Name: "app", LastTerminationState: oomKilledAt(earlier)}},
+			new:  []corev1.ContainerStatus{{Name: "app", LastTerminationState: oomKilledAt(earlier)}},
+		},
+		{
+			name: "statuses reordered",
+			old: []corev1.ContainerStatus{
+				{Name: "app", LastTerminationState: oomKilledAt(earlier)},
+				{Name: "sidecar"},
+			},
+			new: []corev1.ContainerStatus{
+				{Name: "sidecar"},
+				{Name: "app", LastTerminationState: oomKilledAt(earlier)},
+			},
+		},
+		{
+			name: "container added in front",
+			old:  []corev1.ContainerStatus{{Name: "app"}},
+			new: []corev1.ContainerStatus{
+				{Name: "sidecar"},
+				{Name: "app", LastTerminationState: oomKilledAt(later)},
+			},
+			want: true,
+		},
+		{
+			name: "init container newly OOMKilled",
+			init: true,
+			old:  []corev1.ContainerStatus{{Name: "migrate"}},
+			new:  []corev1.ContainerStatus{{Name: "migrate", LastTerminationState: oomKilledAt(later)}},
+			want: true,
+		},
+		{
+			name:      "ephemeral container newly OOMKilled",
+			ephemeral: true,
+			old:       []corev1.ContainerStatus{{Name: "debugger"}},
+			new:       []corev1.ContainerStatus{{Name: "debugger", LastTerminationState: oomKilledAt(later)}},
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldPod, newPod := testPod("default", "web"), testPod("default", "web")
+			switch {
+			case tt.init:
+				oldPod.Status.InitContainerStatuses = tt.old
+				newPod.Status.InitContainerStatuses = tt.new
+			case tt.ephemeral:
+				oldPod.Status.EphemeralContainerStatuses = tt.old
+				newPod.Status.EphemeralContainerStatuses = tt.new
+			default:
+				oldPod.Status.ContainerStatuses = tt.old
+				newPod.Status.ContainerStatuses = tt.new
+			}
+			if got := wasOOMKilled(oldPod, newPod); got != tt.want {
+				t.Errorf("wasOOMKilled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrashLoopBackOffEventIsAWarning(t *testing.T) {
+	pod := testPod("default", "web")
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", Image: "nginx:1.25", RestartCount: 2}}
+	pm, client := newTestMonitor(t, "default", pod)
+	events := startWatching(t, pm)
+
+	pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+	if _, err := client.CoreV1().Pods("default").Update(context.Background(), pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	modified := nextEvent(t, events, "MODIFIED")
+	if modified.Reason != "Container app is in CrashLoopBackOff (restarts=2)" || modified.Severity != severityWarning {
+		t.Errorf("MODIFIED reason/severity = %q/%q, want the CrashLoopBackOff warning", modified.Reason, modified.Severity)
+	}
+}
@@ -0,0 +1,216 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	natsTimeout        = 5 * time.Second
+	natsReconnectWait  = time.Second
+	natsBufferSize     = 1000
+	natsConnectionName = "pod-monitor"
+)
+
+// natsSink publishes each event as JSON to a NATS JetStream subject from its
+// own goroutine and waits for the stream's acknowledgment before publishing
+// the next one, so an acknowledged event is stored. The client reconnects on
+// its own; while it is disconnected, events are dropped, and events queued
+// beyond natsBufferSize are refused.
+type natsSink struct {
+	url     string
+	subject string
+	options []nats.Option
+	logger  *log.Logger
+
+	conn    *nats.Conn
+	js      jetstream.JetStream
+	dropped int
+
+	mu     sync.RWMutex
+	closed bool
+	events chan PodEvent
+	done   chan struct{}
+}
+
+// newNATSSink builds a sink publishing to subject on the server at rawURL,
+// e.g. nats://nats:4222, or tls://nats:4222 to require TLS. credsFile is an
+// optional NATS credentials file and caFile an optional CA bundle verifying
+// the server. It returns nil when rawURL is empty. The connection is opened
+// by run.
+func newNATSSink(rawURL, subject, credsFile, caFile string, logger *log.Logger) (*natsSink, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") || u.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q: must be nats://host:port or tls://host:port", rawURL)
+	}
+
+	subject = strings.TrimSpace(subject)
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n*>") {
+		return nil, fmt.Errorf("invalid NATS subject %q: must be non-empty without spaces or wildcards", subject)
+	}
+
+	s := &natsSink{
+		url:     rawURL,
+		subject: subject,
+		logger:  logger,
+		events:  make(chan PodEvent, natsBufferSize),
+		done:    make(chan struct{}),
+	}
+	s.options = []nats.Option{
+		nats.Name(natsConnectionName),
+		nats.Timeout(natsTimeout),
+		// Keep reconnecting for as long as the monitor runs, including when
+		// NATS is down at startup.
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(natsReconnectWait),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Printf("⚠️  Disconnected from NATS %s: %v", u.Host, err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Printf("✅ Reconnected to NATS %s", conn.ConnectedUrlRedacted())
+		}),
+	}
+
+	if credsFile = strings.TrimSpace(credsFile); credsFile != "" {
+		if _, err := os.Stat(credsFile); err != nil {
+			return nil, fmt.Errorf("invalid NATS credentials file: %v", err)
+		}
+		s.options = append(s.options, nats.UserCredentials(credsFile))
+	}
+	if caFile = strings.TrimSpace(caFile); caFile != "" {
+		if _, err := os.Stat(caFile); err != nil {
+			return nil, fmt.Errorf("invalid NATS CA file: %v", err)
+		}
+		s.options = append(s.options, nats.RootCAs(caFile))
+	}
+	return s, nil
+}
+
+// Emit queues an event for publishing without blocking.
+func (s *natsSink) Emit(event PodEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("NATS buffer full")
+	}
+}
+
+// run connects and publishes queued events until Close is called.
+func (s *natsSink) run() {
+	defer close(s.done)
+
+	conn, err := nats.Connect(s.url, s.options...)
+	if err != nil {
+		// Only invalid options get here; connection failures are retried.
+		s.logger.Printf("❌ Failed to set up the NATS connection: %v", err)
+		for range s.events {
+			s.dropped++
+		}
+		s.logger.Printf("⚠️  %d events were not published to NATS", s.dropped)
+		return
+	}
+	defer conn.Close()
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		s.logger.Printf("❌ Failed to set up NATS JetStream: %v", err)
+		return
+	}
+	s.conn, s.js = conn, js
+
+	for event := range s.events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			s.logger.Printf("❌ Failed to marshal event for NATS: %v", err)
+			continue
+		}
+		s.publish(payload, event.contentType())
+	}
+	if s.dropped > 0 {
+		s.logger.Printf("⚠️  %d events were not published to NATS", s.dropped)
+	}
+}
+
+// Close stops accepting events and waits up to timeout for queued events to
+// be published and acknowledged.
+func (s *natsSink) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		s.logger.Println("⚠️  Timed out publishing events to NATS")
+	}
+}
+
+func (s *natsSink) pending() int {
+	return len(s.events)
+}
+
+// publish sends one event and waits for its acknowledgment. The event is
+// dropped while the client is disconnected, when no stream captures the
+// subject and when JetStream rejects it or does not acknowledge it in time.
+func (s *natsSink) publish(payload []byte, contentType string) {
+	if !s.conn.IsConnected() {
+		s.drop(errors.New("not connected"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), natsTimeout)
+	defer cancel()
+
+	msg := nats.NewMsg(s.subject)
+	msg.Data = payload
+	msg.Header.Set("Content-Type", contentType)
+	if _, err := s.js.PublishMsg(ctx, msg); err != nil {
+		if errors.Is(err, jetstream.ErrNoStreamResponse) || errors.Is(err, nats.ErrNoResponders) {
+			err = fmt.Errorf("no stream captures subject %s", s.subject)
+		}
+		s.drop(err)
+		return
+	}
+
+	if s.dropped > 0 {
+		s.logger.Printf("✅ Publishing to NATS again after dropping %d events", s.dropped)
+		s.dropped = 0
+	}
+}
+
+func (s *natsSink) drop(err error) {
+	if s.dropped == 0 {
+		s.logger.Printf("❌ Failed to publish to NATS: %v; dropping events until it recovers", err)
+	}
+	s.dropped++
+}
@@ -0,0 +1,80 @@
+//go:build integration
+
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// TestNATSSinkPublishesToJetStream needs a NATS server with JetStream
+// enabled, e.g. `docker run -p 4222:4222 nats -js`, at NATS_TEST_URL:
+//
+//	NATS_TEST_URL=nats://localhost:4222 go test -tags integration -run NATS ./pkg/monitor
+func TestNATSSinkPublishesToJetStream(t *testing.T) {
+	serverURL := os.Getenv("NATS_TEST_URL")
+	if serverURL == "" {
+		t.Skip("NATS_TEST_URL not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := nats.Connect(serverURL)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject := "pod-monitor-test." + time.Now().Format("150405.000000")
+	stream, err := js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     "POD_MONITOR_TEST_" + time.Now().Format("150405000000"),
+		Subjects: []string{subject},
+	})
+	if err != nil {
+		t.Fatalf("create stream: %v", err)
+	}
+	defer js.DeleteStream(context.Background(), stream.CachedInfo().Config.Name)
+
+	sink, err := newNATSSink(serverURL, subject, "", "", log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go sink.run()
+
+	want := PodEvent{Timestamp: time.Now(), EventType: "ADDED", PodName: "web", Namespace: "default", Phase: "Pending"}
+	if err := sink.Emit(want); err != nil {
+		t.Fatal(err)
+	}
+	sink.Close(10 * time.Second)
+
+	consumer, err := stream.CreateConsumer(ctx, jetstream.ConsumerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := consumer.Next(jetstream.FetchMaxWait(5 * time.Second))
+	if err != nil {
+		t.Fatalf("no message in the stream: %v", err)
+	}
+	if got := msg.Headers().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var got PodEvent
+	if err := json.Unmarshal(msg.Data(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.EventType != want.EventType || got.PodName != want.PodName || got.Namespace != want.Namespace {
+		t.Errorf("published event = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package monitor
+
+import (
+	"io"
+	"log"
+	"testing"
+)
+
+func TestNewNATSSinkValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		subject string
+		creds   string
+		wantErr bool
+	}{
+		{name: "disabled", url: "", subject: "pod-events"},
+		{name: "plain", url: "nats://nats:4222", subject: "pod-events"},
+		{name: "tls with token", url: "tls://secret@nats:4222", subject: "k8s.pods"},
+		{name: "wrong scheme", url: "http://nats:4222", subject: "pod-events", wantErr: true},
+		{name: "no host", url: "nats://", subject: "pod-events", wantErr: true},
+		{name: "empty subject", url: "nats://nats:4222", subject: " ", wantErr: true},
+		{name: "wildcard subject", url: "nats://nats:4222", subject: "pods.>", wantErr: true},
+		{name: "missing creds file", url: "nats://nats:4222", subject: "pod-events", creds: "/nonexistent.creds", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := newNATSSink(tt.url, tt.subject, tt.creds, "", log.New(io.Discard, "", 0))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newNATSSink error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.url == "" && sink != nil {
+				t.Fatal("empty URL built a sink")
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	zoneLabel               = "topology.kubernetes.io/zone"
+	legacyZoneLabel         = "failure-domain.beta.kubernetes.io/zone"
+	instanceTypeLabel       = "node.kubernetes.io/instance-type"
+	legacyInstanceTypeLabel = "beta.kubernetes.io/instance-type"
+)
+
+type nodeTopology struct {
+	zone         string
+	instanceType string
+}
+
+// nodeLabelCache keeps the zone and instance type of every node so events can
+// be enriched without a node lookup per event. The cache is rebuilt from a
+// full node list periodically; nodes missing from it are fetched on demand.
+type nodeLabelCache struct {
+	clientset kubernetes.Interface
+	interval  time.Duration
+	logger    *log.Logger
+
+	mu    sync.RWMutex
+	nodes map[string]nodeTopology
+}
+
+func newNodeLabelCache(clientset kubernetes.Interface, interval time.Duration, logger *log.Logger) *nodeLabelCache {
+	return &nodeLabelCache{
+		clientset: clientset,
+		interval:  interval,
+		logger:    logger,
+		nodes:     make(map[string]nodeTopology),
+	}
+}
+
+func (c *nodeLabelCache) run(ctx context.Context) {
+	if err := c.refresh(ctx); err != nil {
+		c.logger.Printf("⚠️  Failed to load node labels: %v", err)
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.refresh(ctx); err != nil {
+				c.logger.Printf("⚠️  Failed to refresh node labels: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *nodeLabelCache) refresh(ctx context.Context) error {
+	listCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	nodes, err := c.clientset.CoreV1().Nodes().List(listCtx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	topology := make(map[string]nodeTopology, len(nodes.Items))
+	for i := range nodes.Items {
+		topology[nodes.Items[i].Name] = topologyOf(&nodes.Items[i])
+	}
+
+	c.mu.Lock()
+	c.nodes = topology
+	c.mu.Unlock()
+	return nil
+}
+
+// lookup returns the cached topology of a node, fetching it on a cache miss.
+func (c *nodeLabelCache) lookup(nodeName string) nodeTopology {
+	c.mu.RLock()
+	topology, ok := c.nodes[nodeName]
+	c.mu.RUnlock()
+	if ok {
+		return topology
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	node, err := c.clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		topology = topologyOf(node)
+	case apierrors.IsNotFound(err):
+		// Cache the miss so a deleted node is not looked up on every event.
+	default:
+		c.logger.Printf("⚠️  Failed to look up node %s: %v", nodeName, err)
+		return nodeTopology{}
+	}
+
+	c.mu.Lock()
+	c.nodes[nodeName] = topology
+	c.mu.Unlock()
+	return topology
+}
+
+func topologyOf(node *corev1.Node) nodeTopology {
+	topology := nodeTopology{
+		zone:         node.Labels[zoneLabel],
+		instanceType: node.Labels[instanceTypeLabel],
+	}
+	if topology.zone == "" {
+		topology.zone = node.Labels[legacyZoneLabel]
+	}
+	if topology.instanceType == "" {
+		topology.instanceType = node.Labels[legacyInstanceTypeLabel]
+	}
+	return topology
+}
@@ -0,0 +1,247 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// nodePressureConditions are the node conditions reported when they turn
+// True.
+var nodePressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+}
+
+// nodeWatcher watches nodes and emits NODE_* events when a node's Ready
+// condition flips, when it is cordoned or uncordoned, and when a pressure
+// condition appears. Node events go through the same sinks as pod events,
+// with PodName empty and NodeName set. It reconnects with the same backoff as
+// the pod watchers.
+type nodeWatcher struct {
+	pm *PodMonitor
+
+	retryCount int
+
+	mu    sync.RWMutex
+	nodes map[string]*corev1.Node
+}
+
+func newNodeWatcher(pm *PodMonitor) *nodeWatcher {
+	return &nodeWatcher{
+		pm:    pm,
+		nodes: make(map[string]*corev1.Node),
+	}
+}
+
+func (w *nodeWatcher) run(ctx context.Context) {
+	pm := w.pm
+
+	r := &resourceWatch{
+		logger: pm.logger,
+		list:   w.listNodes,
+		watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return pm.clientset.CoreV1().Nodes().Watch(ctx, metav1.ListOptions{
+				ResourceVersion:     resourceVersion,
+				AllowWatchBookmarks: true,
+			})
+		},
+		handle: func(eventType watch.EventType, obj runtime.Object) bool {
+			node, ok := obj.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			if eventType != watch.Bookmark {
+				w.handleNodeEvent(eventType, node)
+			}
+			return true
+		},
+		backoff: w.backoff,
+		onEvent: func() { w.retryCount = 0 },
+
+		messages: watchMessages{
+			listFailed:  "❌ Failed to list nodes: %v",
+			watchFailed: "❌ Failed to create node watcher: %v",
+			expired:     "⚠️  Node watch resource version expired, relisting nodes",
+			watchError:  "❌ Node watch error: %v",
+			unexpected:  "⚠️  Unexpected object type in node watch: %T",
+			cancelled:   "🛑 Context cancelled, stopping node watcher",
+		},
+	}
+	r.run(ctx)
+}
+
+// listNodes records the current state of every node without emitting events
+// and returns the list's resourceVersion.
+func (w *nodeWatcher) listNodes(ctx context.Context) (string, error) {
+	nodes, err := w.pm.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	tracked := make(map[string]*corev1.Node, len(nodes.Items))
+	for i := range nodes.Items {
+		tracked[nodes.Items[i].Name] = nodes.Items[i].DeepCopy()
+	}
+
+	w.mu.Lock()
+	w.nodes = tracked
+	w.mu.Unlock()
+
+	w.pm.logger.Printf("🖥️  Watching nodes (found %d existing nodes)", len(nodes.Items))
+	return nodes.ResourceVersion, nil
+}
+
+// backoff waits before the next reconnect attempt. Unlike the pod watchers
+// it never gives up.
+func (w *nodeWatcher) backoff(ctx context.Context) error {
+	w.retryCount++
+	backoffDuration := w.pm.backoff.delay(w.retryCount)
+	w.pm.logger.Printf("⚠️  Node watch interrupted, retrying in %v (attempt %d)", backoffDuration, w.retryCount)
+
+	select {
+	case <-time.After(backoffDuration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *nodeWatcher) handleNodeEvent(eventType watch.EventType, node *corev1.Node) {
+	w.mu.Lock()
+	old := w.nodes[node.Name]
+	if eventType == watch.Deleted {
+		delete(w.nodes, node.Name)
+	} else {
+		w.nodes[node.Name] = node.DeepCopy()
+	}
+	w.mu.Unlock()
+
+	if eventType != watch.Modified || old == nil {
+		return
+	}
+	for _, change := range nodeChanges(old, node) {
+		w.pm.logEvent(w.pm.newNodeEvent(node, change))
+	}
+}
+
+type nodeChange struct {
+	eventType string
+	reason    string
+	warning   bool
+}
+
+// nodeChanges lists the transitions between two versions of a node that are
+// worth reporting.
+func nodeChanges(old, node *corev1.Node) []nodeChange {
+	var changes []nodeChange
+
+	wasReady, isReady := nodeReady(old), nodeReady(node)
+	switch {
+	case wasReady && !isReady:
+		reason := "Node Ready condition is no longer True"
+		if condition := nodeCondition(node, corev1.NodeReady); condition != nil && condition.Message != "" {
+			reason += ": " + condition.Message
+		}
+		changes = append(changes, nodeChange{eventType: "NODE_NOT_READY", reason: reason, warning: true})
+	case !wasReady && isReady:
+		changes = append(changes, nodeChange{eventType: "NODE_READY", reason: "Node Ready condition is True"})
+	}
+
+	switch {
+	case !old.Spec.Unschedulable && node.Spec.Unschedulable:
+		changes = append(changes, nodeChange{eventType: "NODE_CORDONED", reason: "Node marked unschedulable"})
+	case old.Spec.Unschedulable && !node.Spec.Unschedulable:
+		changes = append(changes, nodeChange{eventType: "NODE_UNCORDONED", reason: "Node marked schedulable"})
+	}
+
+	var pressures []string
+	for _, conditionType := range nodePressureConditions {
+		if !nodeConditionTrue(old, conditionType) && nodeConditionTrue(node, conditionType) {
+			pressures = append(pressures, string(conditionType))
+		}
+	}
+	if len(pressures) > 0 {
+		changes = append(changes, nodeChange{
+			eventType: "NODE_PRESSURE",
+			reason:    "Node reports " + strings.Join(pressures, ", "),
+			warning:   true,
+		})
+	}
+
+	return changes
+}
+
+// newNodeEvent builds a node event, noting how many tracked pods run on the
+// node so a burst of rescheduling can be tied back to it.
+func (pm *PodMonitor) newNodeEvent(node *corev1.Node, change nodeChange) PodEvent {
+	topology := topologyOf(node)
+	event := PodEvent{
+		Timestamp:    time.Now(),
+		EventType:    change.eventType,
+		NodeName:     node.Name,
+		Labels:       node.Labels,
+		Message:      fmt.Sprintf("Node changed (%d tracked pods on node)", pm.trackedPodsOnNode(node.Name)),
+		Reason:       change.reason,
+		Zone:         topology.zone,
+		InstanceType: topology.instanceType,
+	}
+	if change.warning {
+		event.Severity = severityWarning
+	}
+	return event
+}
+
+// trackedPodsOnNode counts the tracked pods scheduled on a node across all
+// watchers.
+func (pm *PodMonitor) trackedPodsOnNode(nodeName string) int {
+	count := 0
+	for _, w := range pm.podWatchers() {
+		w.mu.RLock()
+		for _, pod := range w.existingPods {
+			if pod.Spec.NodeName == nodeName {
+				count++
+			}
+		}
+		w.mu.RUnlock()
+	}
+	return count
+}
+
+// nodeNotReady reports whether the node watcher last saw the node as not
+// Ready. It is false when nodes are not watched or the node is unknown.
+func (pm *PodMonitor) nodeNotReady(nodeName string) bool {
+	if pm.nodeWatcher == nil {
+		return false
+	}
+	pm.nodeWatcher.mu.RLock()
+	defer pm.nodeWatcher.mu.RUnlock()
+	node, ok := pm.nodeWatcher.nodes[nodeName]
+	return ok && !nodeReady(node)
+}
+
+func nodeReady(node *corev1.Node) bool {
+	return nodeConditionTrue(node, corev1.NodeReady)
+}
+
+func nodeConditionTrue(node *corev1.Node, conditionType corev1.NodeConditionType) bool {
+	condition := nodeCondition(node, conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+func nodeCondition(node *corev1.Node, conditionType corev1.NodeConditionType) *corev1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == conditionType {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
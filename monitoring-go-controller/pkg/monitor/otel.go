@@ -0,0 +1,266 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	otelSpanFlushInterval   = 5 * time.Second
+	otelMetricFlushInterval = 30 * time.Second
+	otelSpanBufferSize      = 2048
+	otelScopeName           = "pod-monitor"
+)
+
+// otelExporter sends spans and metrics to an OpenTelemetry collector using
+// OTLP over HTTP. Metrics are read from the Prometheus registry, so both
+// exports always carry the same values. Delivery is best effort: failures are
+// logged and the data is dropped, and spans are dropped when the buffer is
+// full, so a missing collector never affects watching.
+type otelExporter struct {
+	endpoint string
+	traces   *sdktrace.TracerProvider
+	metrics  *sdkmetric.MeterProvider
+	logger   *log.Logger
+}
+
+// newOTelExporter builds an exporter for the collector at endpoint, the
+// OTLP/HTTP base URL such as http://otel-collector:4318. It returns nil when
+// endpoint is empty.
+func newOTelExporter(endpoint, clusterName string, logger *log.Logger) (*otelExporter, error) {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if endpoint == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid OTel endpoint %q: must be an http(s) URL", endpoint)
+	}
+
+	attributes := []attribute.KeyValue{
+		attribute.String("service.name", "pod-monitor"),
+		attribute.String("service.version", version),
+	}
+	if clusterName != "" {
+		attributes = append(attributes, attribute.String("k8s.cluster.name", clusterName))
+	}
+	res := resource.NewSchemaless(attributes...)
+
+	// Neither exporter connects until the first export.
+	ctx := context.Background()
+	spanExporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint+"/v1/traces"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up OTel trace export: %v", err)
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(endpoint+"/v1/metrics"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up OTel metric export: %v", err)
+	}
+
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Printf("❌ Failed to export telemetry to the OTel collector: %v", err)
+	}))
+
+	return &otelExporter{
+		endpoint: endpoint,
+		traces: sdktrace.NewTracerProvider(
+			sdktrace.WithResource(res),
+			sdktrace.WithBatcher(spanExporter,
+				sdktrace.WithBatchTimeout(otelSpanFlushInterval),
+				sdktrace.WithMaxQueueSize(otelSpanBufferSize)),
+		),
+		metrics: sdkmetric.NewMeterProvider(
+			sdkmetric.WithResource(res),
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter,
+				sdkmetric.WithInterval(otelMetricFlushInterval),
+				sdkmetric.WithProducer(&prometheusProducer{gatherer: prometheus.DefaultGatherer, start: time.Now()}))),
+		),
+		logger: logger,
+	}, nil
+}
+
+// tracer returns the tracer spans are recorded with, a no-op one when OTel
+// export is disabled.
+func (e *otelExporter) tracer() trace.Tracer {
+	if e == nil {
+		return noop.NewTracerProvider().Tracer(otelScopeName)
+	}
+	return e.traces.Tracer(otelScopeName, trace.WithInstrumentationVersion(version))
+}
+
+// startSpan starts a span as a child of any span in ctx. attrs alternates
+// keys and values. The span is a no-op when OTel export is disabled.
+func (pm *PodMonitor) startSpan(ctx context.Context, name string, attrs ...string) (context.Context, trace.Span) {
+	var kvs []attribute.KeyValue
+	for i := 0; i+1 < len(attrs); i += 2 {
+		kvs = append(kvs, attribute.String(attrs[i], attrs[i+1]))
+	}
+	return pm.tracer.Start(ctx, name, trace.WithAttributes(kvs...))
+}
+
+// endSpan ends span, marking it failed when err is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Close exports the remaining spans and the current metrics, waiting up to
+// timeout.
+func (e *otelExporter) Close(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	traceErr := e.traces.Shutdown(ctx)
+	metricErr := e.metrics.Shutdown(ctx)
+	if ctx.Err() != nil {
+		e.logger.Println("⚠️  Timed out exporting telemetry to the OTel collector")
+		return
+	}
+	for _, err := range []error{traceErr, metricErr} {
+		if err != nil {
+			e.logger.Printf("❌ Failed to export telemetry to the OTel collector: %v", err)
+		}
+	}
+}
+
+// prometheusProducer feeds the monitor's own Prometheus metrics into the OTel
+// metric export. Go runtime and process metrics are left to the Prometheus
+// endpoint.
+type prometheusProducer struct {
+	gatherer prometheus.Gatherer
+	start    time.Time
+}
+
+func (p *prometheusProducer) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+	families, err := p.gatherer.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather metrics for OTel export: %v", err)
+	}
+
+	metrics := otelMetrics(families, p.start, time.Now())
+	if len(metrics) == 0 {
+		return nil, nil
+	}
+	return []metricdata.ScopeMetrics{{
+		Scope:   instrumentation.Scope{Name: otelScopeName, Version: version},
+		Metrics: metrics,
+	}}, nil
+}
+
+// otelMetrics converts Prometheus metric families to OTel metrics, with
+// cumulative temporality as Prometheus counts from process start.
+func otelMetrics(families []*dto.MetricFamily, start, now time.Time) []metricdata.Metrics {
+	var metrics []metricdata.Metrics
+	for _, family := range families {
+		name := family.GetName()
+		if strings.HasPrefix(name, "go_") || strings.HasPrefix(name, "process_") || strings.HasPrefix(name, "promhttp_") {
+			continue
+		}
+
+		metric := metricdata.Metrics{Name: name, Description: family.GetHelp()}
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			sum := metricdata.Sum[float64]{Temporality: metricdata.CumulativeTemporality, IsMonotonic: true}
+			for _, m := range family.GetMetric() {
+				sum.DataPoints = append(sum.DataPoints, metricdata.DataPoint[float64]{
+					Attributes: otelLabels(m.GetLabel()),
+					StartTime:  start,
+					Time:       now,
+					Value:      m.GetCounter().GetValue(),
+				})
+			}
+			metric.Data = sum
+		case dto.MetricType_GAUGE:
+			gauge := metricdata.Gauge[float64]{}
+			for _, m := range family.GetMetric() {
+				gauge.DataPoints = append(gauge.DataPoints, metricdata.DataPoint[float64]{
+					Attributes: otelLabels(m.GetLabel()),
+					Time:       now,
+					Value:      m.GetGauge().GetValue(),
+				})
+			}
+			metric.Data = gauge
+		case dto.MetricType_HISTOGRAM:
+			histogram := metricdata.Histogram[float64]{Temporality: metricdata.CumulativeTemporality}
+			for _, m := range family.GetMetric() {
+				histogram.DataPoints = append(histogram.DataPoints, otelHistogramPoint(m, start, now))
+			}
+			metric.Data = histogram
+		case dto.MetricType_SUMMARY:
+			summary := metricdata.Summary{}
+			for _, m := range family.GetMetric() {
+				point := metricdata.SummaryDataPoint{
+					Attributes: otelLabels(m.GetLabel()),
+					StartTime:  start,
+					Time:       now,
+					Count:      m.GetSummary().GetSampleCount(),
+					Sum:        m.GetSummary().GetSampleSum(),
+				}
+				for _, q := range m.GetSummary().GetQuantile() {
+					point.QuantileValues = append(point.QuantileValues, metricdata.QuantileValue{
+						Quantile: q.GetQuantile(),
+						Value:    q.GetValue(),
+					})
+				}
+				summary.DataPoints = append(summary.DataPoints, point)
+			}
+			metric.Data = summary
+		default:
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+// otelHistogramPoint converts Prometheus' cumulative buckets to OTel's
+// per-bucket counts, with a final overflow bucket.
+func otelHistogramPoint(m *dto.Metric, start, now time.Time) metricdata.HistogramDataPoint[float64] {
+	histogram := m.GetHistogram()
+	point := metricdata.HistogramDataPoint[float64]{
+		Attributes: otelLabels(m.GetLabel()),
+		StartTime:  start,
+		Time:       now,
+		Count:      histogram.GetSampleCount(),
+		Sum:        histogram.GetSampleSum(),
+	}
+
+	var previous uint64
+	for _, bucket := range histogram.GetBucket() {
+		point.Bounds = append(point.Bounds, bucket.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	point.BucketCounts = append(point.BucketCounts, histogram.GetSampleCount()-previous)
+	return point
+}
+
+func otelLabels(labels []*dto.LabelPair) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(labels))
+	for _, label := range labels {
+		kvs = append(kvs, attribute.String(label.GetName(), label.GetValue()))
+	}
+	return attribute.NewSet(kvs...)
+}
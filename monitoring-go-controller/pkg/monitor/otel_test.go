@@ -0,0 +1,251 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// otlpCollector records what the monitor exports over OTLP/HTTP.
+type otlpCollector struct {
+	mu      sync.Mutex
+	spans   []*tracepb.Span
+	metrics []*metricpb.Metric
+}
+
+func newOTLPCollector(t *testing.T) (*otlpCollector, *httptest.Server) {
+	c := &otlpCollector{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/traces":
+			var request coltracepb.ExportTraceServiceRequest
+			if err := proto.Unmarshal(body, &request); err != nil {
+				t.Errorf("malformed trace export: %v", err)
+			}
+			for _, rs := range request.GetResourceSpans() {
+				for _, ss := range rs.GetScopeSpans() {
+					c.spans = append(c.spans, ss.GetSpans()...)
+				}
+			}
+		case "/v1/metrics":
+			var request colmetricpb.ExportMetricsServiceRequest
+			if err := proto.Unmarshal(body, &request); err != nil {
+				t.Errorf("malformed metric export: %v", err)
+			}
+			for _, rm := range request.GetResourceMetrics() {
+				for _, sm := range rm.GetScopeMetrics() {
+					c.metrics = append(c.metrics, sm.GetMetrics()...)
+				}
+			}
+		default:
+			t.Errorf("export to unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	t.Cleanup(server.Close)
+	return c, server
+}
+
+func spanAttribute(span *tracepb.Span, key string) string {
+	for _, kv := range span.GetAttributes() {
+		if kv.GetKey() == key {
+			return kv.GetValue().GetStringValue()
+		}
+	}
+	return ""
+}
+
+func TestOTelExportTracesEventsUnderTheirWatchEvent(t *testing.T) {
+	collector, server := newOTLPCollector(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL)
+	pm, client := newTestMonitor(t, "default")
+	events := startWatching(t, pm)
+
+	if _, err := client.CoreV1().Pods("default").Create(context.Background(), testPod("default", "web"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	nextEvent(t, events, "ADDED")
+	pm.otel.Close(5 * time.Second)
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	var handle, emit *tracepb.Span
+	listed := false
+	for _, span := range collector.spans {
+		switch span.GetName() {
+		case "handle pod event":
+			if spanAttribute(span, "event_type") == "ADDED" {
+				handle = span
+			}
+		case "emit event":
+			if spanAttribute(span, "event_type") == "ADDED" {
+				emit = span
+			}
+		case "list pods":
+			listed = spanAttribute(span, "namespace") == "default"
+		}
+	}
+	if !listed {
+		t.Error("no list pods span for namespace default")
+	}
+	if handle == nil || emit == nil {
+		t.Fatalf("missing handle/emit spans for the ADDED event in %d exported spans", len(collector.spans))
+	}
+	if !bytes.Equal(emit.GetTraceId(), handle.GetTraceId()) || !bytes.Equal(emit.GetParentSpanId(), handle.GetSpanId()) {
+		t.Error("emit event span is not a child of the handle pod event span")
+	}
+
+	var processing *metricpb.Metric
+	for _, metric := range collector.metrics {
+		if metric.GetName() == "event_processing_seconds" {
+			processing = metric
+		}
+	}
+	if processing == nil {
+		t.Fatal("event_processing_seconds was not exported")
+	}
+	if points := processing.GetSummary().GetDataPoints(); len(points) != 1 || points[0].GetCount() == 0 {
+		t.Errorf("event_processing_seconds summary points = %v, want one with observations", points)
+	}
+}
+
+func TestPodEventsTotalExemplarLinksTheTrace(t *testing.T) {
+	// exemplarOf returns the trace_id exemplar of pod_events_total for
+	// namespace, empty when there is none.
+	exemplarOf := func(namespace string) string {
+		t.Helper()
+		families, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, family := range families {
+			if family.GetName() != "pod_events_total" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() != "namespace" || label.GetValue() != namespace {
+						continue
+					}
+					for _, exemplarLabel := range metric.GetCounter().GetExemplar().GetLabel() {
+						if exemplarLabel.GetName() == "trace_id" {
+							return exemplarLabel.GetValue()
+						}
+					}
+				}
+			}
+		}
+		return ""
+	}
+
+	t.Setenv("METRICS_ADDR", ":8080")
+	pm, _ := newTestMonitor(t, "default")
+	pm.logEvent(PodEvent{EventType: "ADDED", Namespace: "exemplar-without-otel", PodName: "web"})
+	if traceID := exemplarOf("exemplar-without-otel"); traceID != "" {
+		t.Errorf("exemplar %q attached without OTel export", traceID)
+	}
+
+	_, server := newOTLPCollector(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", server.URL)
+	pm, _ = newTestMonitor(t, "default")
+	defer pm.otel.Close(5 * time.Second)
+	ctx, span := pm.startSpan(context.Background(), "handle pod event")
+	pm.logEventContext(ctx, PodEvent{EventType: "ADDED", Namespace: "exemplar-with-otel", PodName: "web"})
+	span.End()
+	if traceID, want := exemplarOf("exemplar-with-otel"), span.SpanContext().TraceID().String(); traceID != want {
+		t.Errorf("exemplar trace_id = %q, want the event's trace %s", traceID, want)
+	}
+}
+
+func TestOTelMetricsConvertPrometheusTypes(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_events_total"}, []string{"type"})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_pods"})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_seconds", Buckets: []float64{1, 5}})
+	summary := prometheus.NewSummary(prometheus.SummaryOpts{Name: "test_processing_seconds", Objectives: map[float64]float64{0.5: 0.05}})
+	goMetric := prometheus.NewGauge(prometheus.GaugeOpts{Name: "go_test_goroutines"})
+	registry.MustRegister(counter, gauge, histogram, summary, goMetric)
+
+	counter.WithLabelValues("ADDED").Add(3)
+	gauge.Set(7)
+	for _, v := range []float64{0.5, 2, 10} {
+		histogram.Observe(v)
+	}
+	summary.Observe(0.25)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Minute)
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, metric := range otelMetrics(families, start, time.Now()) {
+		metrics[metric.Name] = metric.Data
+	}
+
+	tests := []struct {
+		name  string
+		check func(data metricdata.Aggregation) bool
+	}{
+		{"test_events_total", func(data metricdata.Aggregation) bool {
+			sum, ok := data.(metricdata.Sum[float64])
+			return ok && sum.IsMonotonic && len(sum.DataPoints) == 1 && sum.DataPoints[0].Value == 3 &&
+				sum.DataPoints[0].Attributes.HasValue("type")
+		}},
+		{"test_pods", func(data metricdata.Aggregation) bool {
+			g, ok := data.(metricdata.Gauge[float64])
+			return ok && len(g.DataPoints) == 1 && g.DataPoints[0].Value == 7
+		}},
+		{"test_seconds", func(data metricdata.Aggregation) bool {
+			h, ok := data.(metricdata.Histogram[float64])
+			if !ok || len(h.DataPoints) != 1 {
+				return false
+			}
+			point := h.DataPoints[0]
+			return point.Count == 3 && len(point.BucketCounts) == 3 &&
+				point.BucketCounts[0] == 1 && point.BucketCounts[1] == 1 && point.BucketCounts[2] == 1
+		}},
+		{"test_processing_seconds", func(data metricdata.Aggregation) bool {
+			s, ok := data.(metricdata.Summary)
+			return ok && len(s.DataPoints) == 1 && s.DataPoints[0].Count == 1 &&
+				len(s.DataPoints[0].QuantileValues) == 1 && s.DataPoints[0].QuantileValues[0].Quantile == 0.5
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, ok := metrics[tt.name]
+			if !ok {
+				t.Fatalf("%s was not converted", tt.name)
+			}
+			if !tt.check(data) {
+				t.Errorf("%s converted to %+v", tt.name, data)
+			}
+		})
+	}
+	if _, ok := metrics["go_test_goroutines"]; ok {
+		t.Error("Go runtime metric was exported")
+	}
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ownerKinds are the controller kinds --owner-kind accepts, keyed by their
+// lower-case name. Pods of a Deployment are owned by its ReplicaSets, so a
+// Deployment is resolved through them; the others own their pods directly.
+var ownerKinds = map[string]string{
+	"deployment":  "Deployment",
+	"replicaset":  "ReplicaSet",
+	"statefulset": "StatefulSet",
+	"daemonset":   "DaemonSet",
+	"job":         "Job",
+}
+
+// ownerFilter limits the monitor to the pods of one controller.
+type ownerFilter struct {
+	kind, name string
+
+	// replicaSets caches, by UID, whether a ReplicaSet belongs to the
+	// Deployment. ReplicaSets created by later rollouts are looked up the
+	// first time one of their pods is seen.
+	mu          sync.Mutex
+	replicaSets map[types.UID]bool
+}
+
+// newOwnerFilter validates --owner-kind and --owner-name. It returns nil when
+// both are empty.
+func newOwnerFilter(kind, name string) (*ownerFilter, error) {
+	kind, name = strings.TrimSpace(kind), strings.TrimSpace(name)
+	if kind == "" && name == "" {
+		return nil, nil
+	}
+	if kind == "" || name == "" {
+		return nil, fmt.Errorf("--owner-kind and --owner-name must be set together")
+	}
+	canonical, ok := ownerKinds[strings.ToLower(kind)]
+	if !ok {
+		return nil, fmt.Errorf("invalid --owner-kind %q: must be Deployment, ReplicaSet, StatefulSet, DaemonSet or Job", kind)
+	}
+	return &ownerFilter{kind: canonical, name: name, replicaSets: make(map[types.UID]bool)}, nil
+}
+
+// ownedPod reports whether the pod's controller is the --owner-kind and
+// --owner-name workload, or for a Deployment one of its ReplicaSets.
+func (pm *PodMonitor) ownedPod(pod *corev1.Pod) bool {
+	f := pm.ownerFilter
+	if f == nil {
+		return true
+	}
+
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return false
+	}
+	if f.kind != "Deployment" {
+		return ref.Kind == f.kind && ref.Name == f.name
+	}
+	if ref.Kind != "ReplicaSet" {
+		return false
+	}
+
+	f.mu.Lock()
+	owned, known := f.replicaSets[ref.UID]
+	f.mu.Unlock()
+	if known {
+		return owned
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pm.listTimeout)
+	defer cancel()
+	rs, err := pm.clientset.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		// Not cached, so the next pod of this ReplicaSet retries the lookup.
+		pm.logger.Printf("❌ Failed to get ReplicaSet %s/%s owning pod %s: %v", pod.Namespace, ref.Name, pod.Name, err)
+		return false
+	}
+
+	owner := metav1.GetControllerOf(rs)
+	owned = owner != nil && owner.Kind == "Deployment" && owner.Name == f.name
+	if owned {
+		pm.logger.Printf("🔗 Watching pods of ReplicaSet %s/%s of Deployment %s", pod.Namespace, rs.Name, f.name)
+	}
+
+	f.mu.Lock()
+	f.replicaSets[ref.UID] = owned
+	f.mu.Unlock()
+	return owned
+}
@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// watchStuckPending periodically scans tracked pods for ones that have been
+// Pending for longer than the threshold, e.g. because they are unschedulable
+// or waiting on a volume.
+func (pm *PodMonitor) watchStuckPending(ctx context.Context) {
+	ticker := time.NewTicker(lingerScanInterval(pm.pendingThreshold))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, w := range pm.podWatchers() {
+				for _, event := range w.findStuckPendingPods(time.Now()) {
+					pm.logEvent(event)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// findStuckPendingPods returns one POD_PENDING event for each pod that has
+// crossed the threshold since the last scan. Each pod is reported only once.
+func (w *podWatcher) findStuckPendingPods(now time.Time) []PodEvent {
+	pm := w.pm
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []PodEvent
+	for uid, pod := range w.existingPods {
+		if pod.Status.Phase != corev1.PodPending || w.pendingReported[uid] {
+			continue
+		}
+
+		pending, ok := pm.elapsedSince(w.phaseSince[uid], now)
+		if !ok || pending < pm.pendingThreshold {
+			continue
+		}
+
+		w.pendingReported[uid] = true
+		event := pm.newPodEvent("POD_PENDING", pod)
+		event.Message = "Pod stuck in Pending"
+		event.Reason = fmt.Sprintf("Pending for %v", pending.Round(time.Second))
+		if scheduling := schedulingProblem(pod); scheduling != "" {
+			event.Reason += " (" + scheduling + ")"
+		}
+		if claims := pm.pendingClaims(pod); claims != "" {
+			event.Reason += "; " + claims
+		}
+		event.PhaseDurationSeconds = pending.Seconds()
+		event.Severity = severityWarning
+		events = append(events, event)
+	}
+	return events
+}
+
+// unschedulableReason reports the scheduler failing to place a pod, from the
+// PodScheduled condition it sets alongside its FailedScheduling events. The
+// scheduler rewrites the condition on every retry, so only a new or changed
+// message is reported.
+func unschedulableReason(oldPod, newPod *corev1.Pod) string {
+	message, ok := unschedulableMessage(newPod)
+	if !ok {
+		return ""
+	}
+	if oldMessage, wasUnschedulable := unschedulableMessage(oldPod); wasUnschedulable && oldMessage == message {
+		return ""
+	}
+	return "Unschedulable: " + message
+}
+
+func unschedulableMessage(pod *corev1.Pod) (string, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return condition.Message, true
+		}
+	}
+	return "", false
+}
+
+// schedulingProblem returns the scheduler's explanation from an unsatisfied
+// PodScheduled condition, e.g. "Unschedulable: 0/3 nodes are available".
+func schedulingProblem(pod *corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodScheduled || condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		if condition.Message == "" {
+			return condition.Reason
+		}
+		return condition.Reason + ": " + condition.Message
+	}
+	return ""
+}
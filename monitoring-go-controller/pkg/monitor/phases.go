@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// recordPhase notes when a pod entered its current phase. It must be called
+// with w.mu held, before the pod replaces its previous copy in existingPods.
+func (w *podWatcher) recordPhase(pod *corev1.Pod, now time.Time) {
+	uid := string(pod.UID)
+	oldPod, exists := w.existingPods[uid]
+	switch {
+	case !exists:
+		w.phaseSince[uid] = phaseEntryTime(pod, now)
+	case oldPod.Status.Phase != pod.Status.Phase:
+		w.phaseSince[uid] = now
+	}
+}
+
+// timeInPhase returns how long a tracked pod has been in its current phase.
+// ok is false when the entry time is unknown or unreliable due to clock skew.
+func (w *podWatcher) timeInPhase(uid types.UID) (time.Duration, bool) {
+	w.mu.RLock()
+	since, ok := w.phaseSince[string(uid)]
+	w.mu.RUnlock()
+	if !ok {
+		return 0, false
+	}
+	return w.pm.elapsedSince(since, time.Now())
+}
+
+// elapsedSince returns now - t, clamped so it is never negative: API server
+// timestamps (creation, container start/finish) come from other clocks and
+// can be slightly ahead of ours. ok is false when t is further in the future
+// than CLOCK_SKEW_TOLERANCE allows, in which case the value is meaningless
+// and callers should not report it.
+func (pm *PodMonitor) elapsedSince(t, now time.Time) (time.Duration, bool) {
+	elapsed := now.Sub(t)
+	if elapsed >= 0 {
+		return elapsed, true
+	}
+	if pm.clockSkewTolerance > 0 && -elapsed > pm.clockSkewTolerance {
+		return 0, false
+	}
+	return 0, true
+}
+
+// phaseEntryTime estimates when a pod we have not observed before entered its
+// current phase, using the timestamps the API server already records.
+func phaseEntryTime(pod *corev1.Pod, now time.Time) time.Time {
+	var entered metav1.Time
+
+	switch pod.Status.Phase {
+	case corev1.PodPending:
+		entered = pod.CreationTimestamp
+	case corev1.PodRunning:
+		for _, status := range pod.Status.ContainerStatuses {
+			if running := status.State.Running; running != nil && running.StartedAt.After(entered.Time) {
+				entered = running.StartedAt
+			}
+		}
+	case corev1.PodSucceeded, corev1.PodFailed:
+		for _, status := range pod.Status.ContainerStatuses {
+			if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(entered.Time) {
+				entered = terminated.FinishedAt
+			}
+		}
+	}
+
+	if entered.IsZero() {
+		return now
+	}
+	return entered.Time
+}
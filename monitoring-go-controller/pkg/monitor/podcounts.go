@@ -0,0 +1,199 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// crashLoopCountKey is reported next to the pod phases so that a namespace
+// full of Running-but-crashlooping pods does not look healthy.
+const crashLoopCountKey = "CrashLoopBackOff"
+
+// reportPodCounts periodically emits one NS_POD_COUNTS event per namespace
+// with the number of tracked pods in each phase.
+func (pm *PodMonitor) reportPodCounts(ctx context.Context) {
+	ticker := time.NewTicker(pm.podCountInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			pm.emitPodCounts()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// trackedPodLogInterval is how often the total number of tracked pods is
+// logged in cluster-wide mode, where the tracked set can grow large.
+const trackedPodLogInterval = 5 * time.Minute
+
+// reportTrackedPodTotal periodically logs how many pods are tracked across
+// all watchers.
+func (pm *PodMonitor) reportTrackedPodTotal(ctx context.Context) {
+	ticker := time.NewTicker(trackedPodLogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			pm.logger.Printf("📦 Tracking %d pods across all namespaces", pm.trackedPodTotal())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (pm *PodMonitor) trackedPodTotal() int {
+	total := 0
+	for _, w := range pm.podWatchers() {
+		w.mu.RLock()
+		total += len(w.existingPods)
+		w.mu.RUnlock()
+	}
+	return total
+}
+
+func (pm *PodMonitor) emitPodCounts() {
+	counts := make(map[string]map[string]int)
+	for _, namespace := range pm.watchedNamespaces() {
+		counts[namespace] = make(map[string]int)
+	}
+
+	for _, w := range pm.podWatchers() {
+		w.mu.RLock()
+		for _, pod := range w.existingPods {
+			nsCounts, ok := counts[pod.Namespace]
+			if !ok {
+				nsCounts = make(map[string]int)
+				counts[pod.Namespace] = nsCounts
+			}
+			nsCounts[string(pod.Status.Phase)]++
+			if inCrashLoop(pod) {
+				nsCounts[crashLoopCountKey]++
+			}
+		}
+		w.mu.RUnlock()
+	}
+
+	namespaces := make([]string, 0, len(counts))
+	for namespace := range counts {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	now := time.Now()
+	for _, namespace := range namespaces {
+		total := 0
+		for key, count := range counts[namespace] {
+			if key != crashLoopCountKey {
+				total += count
+			}
+		}
+
+		pm.logEvent(PodEvent{
+			Timestamp: now,
+			EventType: "NS_POD_COUNTS",
+			Namespace: namespace,
+			Counts:    counts[namespace],
+			Message:   fmt.Sprintf("%d pods tracked", total),
+		})
+	}
+}
+
+// inCrashLoop reports whether any container, including init and ephemeral
+// containers, is in CrashLoopBackOff.
+func inCrashLoop(pod *corev1.Pod) bool {
+	for _, statuses := range [][]corev1.ContainerStatus{
+		pod.Status.InitContainerStatuses,
+		pod.Status.ContainerStatuses,
+		pod.Status.EphemeralContainerStatuses,
+	} {
+		for _, status := range statuses {
+			if waitingReason(status) == "CrashLoopBackOff" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// waitingReason returns the reason a container is waiting, or "" when it is
+// not waiting.
+func waitingReason(status corev1.ContainerStatus) string {
+	if status.State.Waiting == nil {
+		return ""
+	}
+	return status.State.Waiting.Reason
+}
+
+// formatCounts renders counts as "Pending=1, Running=3" with stable ordering.
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", key, counts[key]))
+	}
+	if len(parts) == 0 {
+		return "no pods"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// oomKilled returns the container's last termination when it was killed for
+// exceeding its memory limit, or nil otherwise.
+func oomKilled(status corev1.ContainerStatus) *corev1.ContainerStateTerminated {
+	terminated := status.LastTerminationState.Terminated
+	if terminated == nil || terminated.Reason != "OOMKilled" {
+		return nil
+	}
+	return terminated
+}
+
+// newlyOOMKilled returns the container's OOMKilled termination if it was not
+// already reported in the previous status.
+func newlyOOMKilled(old, status corev1.ContainerStatus) *corev1.ContainerStateTerminated {
+	terminated := oomKilled(status)
+	if terminated == nil {
+		return nil
+	}
+	if previous := oomKilled(old); previous != nil && previous.FinishedAt.Equal(&terminated.FinishedAt) {
+		return nil
+	}
+	return terminated
+}
+
+// wasOOMKilled reports whether any container, including init and ephemeral
+// containers, was OOMKilled between the two pod versions.
+func wasOOMKilled(oldPod, newPod *corev1.Pod) bool {
+	return anyNewlyOOMKilled(oldPod.Status.InitContainerStatuses, newPod.Status.InitContainerStatuses) ||
+		anyNewlyOOMKilled(oldPod.Status.ContainerStatuses, newPod.Status.ContainerStatuses) ||
+		anyNewlyOOMKilled(oldPod.Status.EphemeralContainerStatuses, newPod.Status.EphemeralContainerStatuses)
+}
+
+// anyNewlyOOMKilled reports whether one kind of container was OOMKilled.
+// Statuses are matched by container name, as in containerStatusReasons, so
+// reordered or added containers are not compared with the wrong old status.
+func anyNewlyOOMKilled(oldStatuses, newStatuses []corev1.ContainerStatus) bool {
+	oldByName := make(map[string]corev1.ContainerStatus, len(oldStatuses))
+	for _, status := range oldStatuses {
+		oldByName[status.Name] = status
+	}
+	for _, status := range newStatuses {
+		if old, existed := oldByName[status.Name]; existed && newlyOOMKilled(old, status) != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podVerbs are the pod permissions every watch mode needs.
+var podVerbs = []string{"list", "watch"}
+
+// checkPodAccess asks the API server, with a SelfSubjectAccessReview per
+// namespace and verb, whether the monitor may list and watch pods where it is
+// about to. Each denial is logged with the RBAC rule that grants it. It
+// returns an error naming the namespaces that are missing permissions; a
+// review that cannot be made is logged and not counted as a denial.
+func (pm *PodMonitor) checkPodAccess(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	namespaces := pm.watchedNamespaces()
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var denied []string
+	for _, namespace := range namespaces {
+		where := "namespace " + namespace
+		if namespace == metav1.NamespaceAll {
+			where = "all namespaces"
+		}
+
+		var missing []string
+		for _, verb := range podVerbs {
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: namespace,
+						Verb:      verb,
+						Resource:  "pods",
+					},
+				},
+			}
+			result, err := pm.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+			if err != nil {
+				pm.logger.Printf("⚠️  Could not check permission to %s pods in %s: %v", verb, where, err)
+				continue
+			}
+			if !result.Status.Allowed {
+				missing = append(missing, verb)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		if namespace == metav1.NamespaceAll {
+			denied = append(denied, "all namespaces")
+			pm.logger.Printf("🔒 Missing permission to %s pods cluster-wide. Grant it with a ClusterRole and ClusterRoleBinding for the monitor's identity with the rule: %s",
+				strings.Join(missing, " and "), podRule())
+		} else {
+			denied = append(denied, namespace)
+			pm.logger.Printf("🔒 Missing permission to %s pods in namespace %q. Grant it with a Role and RoleBinding in %q for the monitor's identity with the rule: %s",
+				strings.Join(missing, " and "), namespace, namespace, podRule())
+		}
+	}
+
+	if len(denied) > 0 {
+		return fmt.Errorf("missing permission to list and watch pods in %s", strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// podRule is the RBAC rule granting podVerbs, in the form it takes in a
+// Role manifest.
+func podRule() string {
+	return fmt.Sprintf(`{apiGroups: [""], resources: ["pods"], verbs: ["%s"]}`, strings.Join(podVerbs, `", "`))
+}
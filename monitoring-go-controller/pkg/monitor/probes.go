@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// probeFailure is the latest kubelet Unhealthy event for one container.
+type probeFailure struct {
+	probe   string
+	message string
+}
+
+// recordProbeFailure remembers a kubelet probe failure for the container it
+// names, so that the pod's next readiness or restart change can say which
+// probe failed and why. Failures for pods we do not track are ignored.
+func (pm *PodMonitor) recordProbeFailure(k8sEvent *corev1.Event) {
+	container := probeContainer(k8sEvent.InvolvedObject.FieldPath)
+	probe := probeType(k8sEvent.Message)
+	if container == "" || probe == "unknown" {
+		return
+	}
+
+	uid := string(k8sEvent.InvolvedObject.UID)
+	for _, w := range pm.podWatchers() {
+		w.mu.Lock()
+		if _, tracked := w.existingPods[uid]; tracked {
+			if w.probeFailures[uid] == nil {
+				w.probeFailures[uid] = make(map[string]probeFailure)
+			}
+			w.probeFailures[uid][container+"/"+probe] = probeFailure{probe: probe, message: k8sEvent.Message}
+			w.mu.Unlock()
+			return
+		}
+		w.mu.Unlock()
+	}
+}
+
+// takeProbeFailure returns and forgets the recorded failure of one probe of a
+// container, reporting false when there is none.
+func (pm *PodMonitor) takeProbeFailure(uid types.UID, container, probe string) (probeFailure, bool) {
+	for _, w := range pm.podWatchers() {
+		w.mu.Lock()
+		failures, tracked := w.probeFailures[string(uid)]
+		if tracked {
+			failure, ok := failures[container+"/"+probe]
+			delete(failures, container+"/"+probe)
+			w.mu.Unlock()
+			return failure, ok
+		}
+		w.mu.Unlock()
+	}
+	return probeFailure{}, false
+}
+
+// probeFailureReason describes a container's readiness or restart change by
+// the probe failure that caused it, e.g. "Container web readiness probe
+// failing: HTTP probe failed with statuscode: 500". It returns "" when no
+// matching probe failure was seen.
+func (pm *PodMonitor) probeFailureReason(uid types.UID, container, probe string) string {
+	failure, ok := pm.takeProbeFailure(uid, container, probe)
+	if !ok {
+		return ""
+	}
+	message := failure.message
+	if i := strings.Index(message, "probe failed: "); i >= 0 {
+		message = message[i+len("probe failed: "):]
+	}
+	return fmt.Sprintf("Container %s %s probe failing: %s", container, failure.probe, strings.TrimSpace(message))
+}
+
+// probeContainer extracts the container name from an event's field path,
+// such as "spec.containers{web}".
+func probeContainer(fieldPath string) string {
+	name, found := strings.CutPrefix(fieldPath, "spec.containers{")
+	if !found || !strings.HasSuffix(name, "}") {
+		return ""
+	}
+	return strings.TrimSuffix(name, "}")
+}
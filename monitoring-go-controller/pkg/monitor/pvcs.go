@@ -0,0 +1,287 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// PVCEvent describes the PersistentVolumeClaim a PVC_* event is about.
+type PVCEvent struct {
+	Name          string `json:"name"`
+	StorageClass  string `json:"storage_class,omitempty"`
+	RequestedSize string `json:"requested_size,omitempty"`
+	Phase         string `json:"phase"`
+	Volume        string `json:"volume,omitempty"`
+}
+
+// pvcWatcher watches PersistentVolumeClaims in one namespace, or in all
+// namespaces when namespace is empty. It emits PVC_BOUND and PVC_LOST when a
+// claim changes phase, and PVC_PENDING once for claims left Pending longer
+// than the threshold. Like the node watcher it never gives up reconnecting.
+type pvcWatcher struct {
+	pm        *PodMonitor
+	namespace string
+
+	retryCount int
+
+	mu              sync.RWMutex
+	claims          map[string]*corev1.PersistentVolumeClaim
+	pendingReported map[string]bool
+}
+
+func newPVCWatcher(pm *PodMonitor, namespace string) *pvcWatcher {
+	return &pvcWatcher{
+		pm:              pm,
+		namespace:       namespace,
+		claims:          make(map[string]*corev1.PersistentVolumeClaim),
+		pendingReported: make(map[string]bool),
+	}
+}
+
+func (w *pvcWatcher) run(ctx context.Context) {
+	pm := w.pm
+
+	go w.watchStuckPending(ctx)
+
+	r := &resourceWatch{
+		logger: pm.logger,
+		list:   w.listClaims,
+		watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return pm.clientset.CoreV1().PersistentVolumeClaims(w.namespace).Watch(ctx, metav1.ListOptions{
+				ResourceVersion:     resourceVersion,
+				AllowWatchBookmarks: true,
+			})
+		},
+		handle: func(eventType watch.EventType, obj runtime.Object) bool {
+			claim, ok := obj.(*corev1.PersistentVolumeClaim)
+			if !ok {
+				return false
+			}
+			if eventType != watch.Bookmark {
+				w.handleClaimEvent(eventType, claim)
+			}
+			return true
+		},
+		backoff: w.backoff,
+		onEvent: func() { w.retryCount = 0 },
+
+		messages: watchMessages{
+			listFailed:  "❌ Failed to list PersistentVolumeClaims: %v",
+			watchFailed: "❌ Failed to create PersistentVolumeClaim watcher: %v",
+			expired:     "⚠️  PersistentVolumeClaim watch resource version expired, relisting",
+			watchError:  "❌ PersistentVolumeClaim watch error: %v",
+			unexpected:  "⚠️  Unexpected object type in PersistentVolumeClaim watch: %T",
+			cancelled:   "🛑 Context cancelled, stopping PersistentVolumeClaim watcher",
+		},
+	}
+	r.run(ctx)
+}
+
+// listClaims records the current claims without emitting events and returns
+// the list's resourceVersion.
+func (w *pvcWatcher) listClaims(ctx context.Context) (string, error) {
+	claims, err := w.pm.clientset.CoreV1().PersistentVolumeClaims(w.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	tracked := make(map[string]*corev1.PersistentVolumeClaim, len(claims.Items))
+	for i := range claims.Items {
+		tracked[claimKey(claims.Items[i].Namespace, claims.Items[i].Name)] = claims.Items[i].DeepCopy()
+	}
+
+	w.mu.Lock()
+	w.claims = tracked
+	for key := range w.pendingReported {
+		if _, exists := tracked[key]; !exists {
+			delete(w.pendingReported, key)
+		}
+	}
+	w.mu.Unlock()
+
+	w.pm.logger.Printf("💾 Watching PersistentVolumeClaims in namespace %s (found %d)", w.label(), len(claims.Items))
+	return claims.ResourceVersion, nil
+}
+
+func (w *pvcWatcher) handleClaimEvent(eventType watch.EventType, claim *corev1.PersistentVolumeClaim) {
+	key := claimKey(claim.Namespace, claim.Name)
+
+	w.mu.Lock()
+	old := w.claims[key]
+	if eventType == watch.Deleted {
+		delete(w.claims, key)
+		delete(w.pendingReported, key)
+	} else {
+		w.claims[key] = claim.DeepCopy()
+	}
+	w.mu.Unlock()
+
+	if eventType != watch.Modified || old == nil || old.Status.Phase == claim.Status.Phase {
+		return
+	}
+	if !w.pm.namespaceInScope(claim.Namespace) {
+		return
+	}
+
+	switch claim.Status.Phase {
+	case corev1.ClaimBound:
+		event := w.pm.newPVCEvent("PVC_BOUND", claim)
+		event.Reason = fmt.Sprintf("Claim bound to volume %s", claim.Spec.VolumeName)
+		if old.Status.Phase == corev1.ClaimPending {
+			if pending, ok := w.pm.elapsedSince(claim.CreationTimestamp.Time, time.Now()); ok {
+				event.Reason += fmt.Sprintf(" after %v Pending", pending.Round(time.Second))
+				event.PhaseDurationSeconds = pending.Seconds()
+			}
+		}
+		w.pm.logEvent(event)
+	case corev1.ClaimLost:
+		event := w.pm.newPVCEvent("PVC_LOST", claim)
+		event.Reason = fmt.Sprintf("Claim lost its volume %s", claim.Spec.VolumeName)
+		event.Severity = severityWarning
+		w.pm.logEvent(event)
+	}
+}
+
+// watchStuckPending periodically reports claims that have been Pending for
+// longer than the threshold, once per claim.
+func (w *pvcWatcher) watchStuckPending(ctx context.Context) {
+	threshold := w.pm.pvcPendingThreshold
+	if threshold <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(lingerScanInterval(threshold))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, event := range w.findStuckPendingClaims(time.Now()) {
+				w.pm.logEvent(event)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *pvcWatcher) findStuckPendingClaims(now time.Time) []PodEvent {
+	pm := w.pm
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []PodEvent
+	for key, claim := range w.claims {
+		if claim.Status.Phase != corev1.ClaimPending || w.pendingReported[key] || !pm.namespaceInScope(claim.Namespace) {
+			continue
+		}
+
+		pending, ok := pm.elapsedSince(claim.CreationTimestamp.Time, now)
+		if !ok || pending < pm.pvcPendingThreshold {
+			continue
+		}
+
+		w.pendingReported[key] = true
+		event := pm.newPVCEvent("PVC_PENDING", claim)
+		event.Reason = fmt.Sprintf("Claim Pending for %v", pending.Round(time.Second))
+		event.PhaseDurationSeconds = pending.Seconds()
+		event.Severity = severityWarning
+		events = append(events, event)
+	}
+	return events
+}
+
+// backoff waits before the next reconnect attempt and never gives up.
+func (w *pvcWatcher) backoff(ctx context.Context) error {
+	w.retryCount++
+	backoffDuration := w.pm.backoff.delay(w.retryCount)
+	w.pm.logger.Printf("⚠️  PersistentVolumeClaim watch interrupted, retrying in %v (attempt %d)", backoffDuration, w.retryCount)
+
+	select {
+	case <-time.After(backoffDuration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// label names the watched namespace in log lines.
+func (w *pvcWatcher) label() string {
+	if w.namespace == "" {
+		return namespaceLabel(nil)
+	}
+	return w.namespace
+}
+
+func claimKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// newPVCEvent builds a PVC_* event. It has no pod name; the claim is
+// described in PVC.
+func (pm *PodMonitor) newPVCEvent(eventType string, claim *corev1.PersistentVolumeClaim) PodEvent {
+	info := &PVCEvent{
+		Name:   claim.Name,
+		Phase:  string(claim.Status.Phase),
+		Volume: claim.Spec.VolumeName,
+	}
+	if claim.Spec.StorageClassName != nil {
+		info.StorageClass = *claim.Spec.StorageClassName
+	}
+	if size, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		info.RequestedSize = size.String()
+	}
+
+	return PodEvent{
+		Timestamp: time.Now(),
+		EventType: eventType,
+		Namespace: claim.Namespace,
+		Labels:    claim.Labels,
+		Message:   "PersistentVolumeClaim " + strings.ToLower(string(claim.Status.Phase)),
+		PVC:       info,
+	}
+}
+
+// pendingClaims names the pod's PersistentVolumeClaims that the PVC watchers
+// last saw Pending, e.g. "waiting for PVC data (Pending, storage class
+// fast)". It is empty when PVCs are not watched or all claims are bound.
+func (pm *PodMonitor) pendingClaims(pod *corev1.Pod) string {
+	if len(pm.pvcWatchers) == 0 {
+		return ""
+	}
+
+	var waiting []string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		key := claimKey(pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+		for _, w := range pm.pvcWatchers {
+			w.mu.RLock()
+			claim, ok := w.claims[key]
+			w.mu.RUnlock()
+			if !ok || claim.Status.Phase != corev1.ClaimPending {
+				continue
+			}
+			description := "PVC " + claim.Name + " (Pending"
+			if claim.Spec.StorageClassName != nil {
+				description += ", storage class " + *claim.Spec.StorageClassName
+			}
+			waiting = append(waiting, description+")")
+			break
+		}
+	}
+	if len(waiting) == 0 {
+		return ""
+	}
+	return "waiting for " + strings.Join(waiting, ", ")
+}
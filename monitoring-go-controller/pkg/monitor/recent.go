@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// recentEvents keeps the last N emitted events in memory for debugging.
+type recentEvents struct {
+	mu     sync.Mutex
+	events []PodEvent
+	next   int
+	full   bool
+}
+
+func newRecentEvents(size int) *recentEvents {
+	if size <= 0 {
+		return nil
+	}
+	return &recentEvents{events: make([]PodEvent, size)}
+}
+
+func (r *recentEvents) add(event PodEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events[r.next] = event
+	r.next++
+	if r.next == len(r.events) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// snapshot returns the buffered events, newest first.
+func (r *recentEvents) snapshot() []PodEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.events)
+	}
+
+	snapshot := make([]PodEvent, 0, count)
+	for i := 1; i <= count; i++ {
+		snapshot = append(snapshot, r.events[(r.next-i+len(r.events))%len(r.events)])
+	}
+	return snapshot
+}
+
+// handleRecentEvents serves the in-memory event buffer, newest first.
+func (pm *PodMonitor) handleRecentEvents(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rw.Header().Set("Allow", http.MethodGet)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !pm.authorized(r) {
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(pm.recent.snapshot())
+}
@@ -0,0 +1,127 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRecentEventsKeepsNewestFirst(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		added int
+		want  string
+	}{
+		{name: "empty", size: 3, added: 0, want: ""},
+		{name: "partly filled", size: 3, added: 2, want: "pod-1 pod-0"},
+		{name: "exactly full", size: 3, added: 3, want: "pod-2 pod-1 pod-0"},
+		{name: "wrapped", size: 3, added: 7, want: "pod-6 pod-5 pod-4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recent := newRecentEvents(tt.size)
+			for i := 0; i < tt.added; i++ {
+				recent.add(PodEvent{PodName: fmt.Sprintf("pod-%d", i)})
+			}
+			var names []string
+			for _, event := range recent.snapshot() {
+				names = append(names, event.PodName)
+			}
+			if got := strings.Join(names, " "); got != tt.want {
+				t.Errorf("snapshot = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if newRecentEvents(0) != nil {
+		t.Error("a zero-size buffer is not disabled")
+	}
+}
+
+// Run with -race: appends from many goroutines interleave with reads.
+func TestRecentEventsConcurrentAccess(t *testing.T) {
+	const writers, perWriter, size = 8, 200, 50
+	recent := newRecentEvents(size)
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				recent.add(PodEvent{PodName: fmt.Sprintf("writer-%d", w), Namespace: fmt.Sprint(i)})
+			}
+		}(w)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+		if n := len(recent.snapshot()); n > size {
+			t.Fatalf("snapshot has %d events, want at most %d", n, size)
+		}
+	}
+	if n := len(recent.snapshot()); n != size {
+		t.Errorf("snapshot after %d appends has %d events, want %d", writers*perWriter, n, size)
+	}
+}
+
+func TestHandleRecentEvents(t *testing.T) {
+	pm, _ := newTestMonitor(t, "default")
+	pm.recent = newRecentEvents(2)
+	pm.apiToken = "s3cret"
+	for _, name := range []string{"old", "middle", "new"} {
+		pm.logEvent(PodEvent{EventType: "ADDED", PodName: name, Namespace: "default"})
+	}
+	server := httptest.NewServer(http.HandlerFunc(pm.handleRecentEvents))
+	defer server.Close()
+
+	get := func(token string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/events/recent", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	if resp := get(""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status without a token = %s, want 401", resp.Status)
+	}
+
+	resp := get("s3cret")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %s, want 200 OK", resp.Status)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	var events []PodEvent
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].PodName != "new" || events[1].PodName != "middle" {
+		t.Errorf("recent events = %+v, want new then middle", events)
+	}
+}
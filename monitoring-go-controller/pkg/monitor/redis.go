@@ -0,0 +1,182 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisTimeout      = 5 * time.Second
+	redisRetryInitial = time.Second
+	redisRetryMax     = 30 * time.Second
+	redisBufferSize   = 1000
+)
+
+// redisSink publishes each event as JSON to a Redis pub/sub channel from its
+// own goroutine. The client redials broken connections itself; while Redis is
+// unreachable, events are dropped without trying it until retryAt, which backs
+// off up to redisRetryMax.
+type redisSink struct {
+	addr    string
+	channel string
+	options *redis.Options
+	logger  *log.Logger
+
+	client *redis.Client
+	// retryAt is when Redis may be tried again after a connection failure.
+	retryAt    time.Time
+	retryDelay time.Duration
+	dropped    int
+
+	mu     sync.RWMutex
+	closed bool
+	events chan PodEvent
+	done   chan struct{}
+}
+
+// newRedisSink builds a sink publishing to channel on addr (host:port),
+// reading REDIS_USERNAME and REDIS_PASSWORD. It returns nil when addr is
+// empty.
+func newRedisSink(addr, channel string, useTLS bool, logger *log.Logger) (*redisSink, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return nil, nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis address %q: %v", addr, err)
+	}
+
+	channel = strings.TrimSpace(channel)
+	if channel == "" {
+		return nil, errors.New("a Redis channel is required when a Redis address is set")
+	}
+
+	s := &redisSink{
+		addr:    addr,
+		channel: channel,
+		options: &redis.Options{
+			Addr:         addr,
+			Username:     os.Getenv("REDIS_USERNAME"),
+			Password:     os.Getenv("REDIS_PASSWORD"),
+			DialTimeout:  redisTimeout,
+			ReadTimeout:  redisTimeout,
+			WriteTimeout: redisTimeout,
+			// run is the only caller, and one connection keeps events in order.
+			PoolSize: 1,
+		},
+		logger: logger,
+		events: make(chan PodEvent, redisBufferSize),
+		done:   make(chan struct{}),
+	}
+	if useTLS {
+		s.options.TLSConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	}
+	return s, nil
+}
+
+// Emit queues an event for publishing without blocking.
+func (s *redisSink) Emit(event PodEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("Redis buffer full")
+	}
+}
+
+// run publishes queued events until Close is called.
+func (s *redisSink) run() {
+	defer close(s.done)
+
+	s.client = redis.NewClient(s.options)
+	defer s.client.Close()
+
+	for event := range s.events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			s.logger.Printf("❌ Failed to marshal event for Redis: %v", err)
+			continue
+		}
+		s.publish(payload)
+	}
+	if s.dropped > 0 {
+		s.logger.Printf("⚠️  %d events were not published to Redis", s.dropped)
+	}
+}
+
+// Close stops accepting events and waits up to timeout for queued events to
+// be published.
+func (s *redisSink) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		s.logger.Println("⚠️  Timed out publishing events to Redis")
+	}
+}
+
+func (s *redisSink) pending() int {
+	return len(s.events)
+}
+
+// publish sends one event. The event is dropped when Redis rejects it, and
+// when Redis cannot be reached, after which it is not tried again until
+// retryAt.
+func (s *redisSink) publish(payload []byte) {
+	if time.Now().Before(s.retryAt) {
+		s.drop(errors.New("waiting to reconnect"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := s.client.Publish(ctx, s.channel, payload).Err(); err != nil {
+		var replyErr redis.Error
+		if !errors.As(err, &replyErr) {
+			s.retryDelay = min(max(2*s.retryDelay, redisRetryInitial), redisRetryMax)
+			s.retryAt = time.Now().Add(s.retryDelay)
+		}
+		s.drop(err)
+		return
+	}
+
+	if s.dropped > 0 {
+		s.logger.Printf("✅ Reconnected to Redis %s after dropping %d events", s.addr, s.dropped)
+		s.dropped = 0
+	}
+	s.retryDelay = 0
+}
+
+func (s *redisSink) drop(err error) {
+	if s.dropped == 0 {
+		s.logger.Printf("❌ Failed to publish to Redis %s: %v; dropping events until it recovers", s.addr, err)
+	}
+	s.dropped++
+}
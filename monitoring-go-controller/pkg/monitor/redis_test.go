@@ -0,0 +1,109 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestNewRedisSinkValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		addr    string
+		channel string
+		wantErr bool
+	}{
+		{name: "disabled", addr: "", channel: "pod-events"},
+		{name: "host and port", addr: "redis:6379", channel: "pod-events"},
+		{name: "missing port", addr: "redis", channel: "pod-events", wantErr: true},
+		{name: "empty channel", addr: "redis:6379", channel: " ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := newRedisSink(tt.addr, tt.channel, false, log.New(io.Discard, "", 0))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newRedisSink error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.addr == "" && sink != nil {
+				t.Fatal("empty address built a sink")
+			}
+		})
+	}
+}
+
+// publishToMiniredis runs a Redis sink against server, emits event and
+// returns what a subscriber to channel received.
+func publishToMiniredis(t *testing.T, server *miniredis.Miniredis, channel string, event PodEvent) []miniredis.PubsubMessage {
+	t.Helper()
+
+	sink, err := newRedisSink(server.Addr(), channel, false, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := server.NewSubscriber()
+	defer sub.Close()
+	sub.Subscribe(channel)
+
+	// miniredis hands messages to subscribers before replying to PUBLISH.
+	var received []miniredis.PubsubMessage
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for {
+			select {
+			case msg := <-sub.Messages():
+				received = append(received, msg)
+			case <-time.After(200 * time.Millisecond):
+				return
+			}
+		}
+	}()
+
+	go sink.run()
+	if err := sink.Emit(event); err != nil {
+		t.Fatal(err)
+	}
+	sink.Close(5 * time.Second)
+	<-collected
+	return received
+}
+
+func TestRedisSinkPublishesEvent(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	event := PodEvent{SchemaVersion: schemaVersion1, EventType: "ADDED", PodName: "web", Namespace: "default"}
+	received := publishToMiniredis(t, server, "pod-events", event)
+	if len(received) != 1 {
+		t.Fatalf("subscriber got %d messages, want 1", len(received))
+	}
+	if received[0].Channel != "pod-events" {
+		t.Errorf("published to %q, want pod-events", received[0].Channel)
+	}
+	var got PodEvent
+	if err := json.Unmarshal([]byte(received[0].Message), &got); err != nil {
+		t.Fatalf("payload is not a JSON event: %v", err)
+	}
+	if got.EventType != "ADDED" || got.PodName != "web" || got.Namespace != "default" {
+		t.Errorf("published event = %+v, want ADDED default/web", got)
+	}
+}
+
+func TestRedisSinkAuthenticates(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireUserAuth("monitor", "secret")
+
+	t.Setenv("REDIS_USERNAME", "monitor")
+	t.Setenv("REDIS_PASSWORD", "secret")
+	if received := publishToMiniredis(t, server, "pod-events", PodEvent{EventType: "ADDED"}); len(received) != 1 {
+		t.Fatalf("subscriber got %d messages with valid credentials, want 1", len(received))
+	}
+
+	t.Setenv("REDIS_PASSWORD", "wrong")
+	if received := publishToMiniredis(t, server, "pod-events", PodEvent{EventType: "ADDED"}); len(received) != 0 {
+		t.Fatalf("subscriber got %d messages with a wrong password, want 0", len(received))
+	}
+}
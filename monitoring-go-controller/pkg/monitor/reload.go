@@ -0,0 +1,244 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// hotReloadSettings are the Config fields a reload applies in place.
+var hotReloadSettings = map[string]bool{
+	"LogLevel":          true,
+	"EventTypes":        true,
+	"MinSeverity":       true,
+	"ExcludeNamespaces": true,
+	"ExcludePodRegex":   true,
+	"IncludePodRegex":   true,
+}
+
+// rewatchSettings are the Config fields that decide which pods are watched.
+// A reload that changes them stops the watches and starts new ones.
+var rewatchSettings = map[string]bool{
+	"Namespaces":    true,
+	"AllNamespaces": true,
+	"FieldSelector": true,
+	"OwnerKind":     true,
+	"OwnerName":     true,
+}
+
+// watchScope is what rewatchSettings resolve to. config is the Config the
+// new watchers are built from.
+type watchScope struct {
+	config        Config
+	namespaces    []string
+	fieldSelector string
+	ownerFilter   *ownerFilter
+}
+
+func newWatchScope(cfg Config) (watchScope, error) {
+	namespaces, err := normalizeNamespaces(cfg.Namespaces)
+	if err != nil {
+		return watchScope{}, err
+	}
+
+	fieldSelector, err := fields.ParseSelector(cfg.FieldSelector)
+	if err != nil {
+		return watchScope{}, fmt.Errorf("invalid field selector %q: %v", cfg.FieldSelector, err)
+	}
+
+	ownerFilter, err := newOwnerFilter(cfg.OwnerKind, cfg.OwnerName)
+	if err != nil {
+		return watchScope{}, err
+	}
+	return watchScope{config: cfg, namespaces: namespaces, fieldSelector: fieldSelector.String(), ownerFilter: ownerFilter}, nil
+}
+
+// sameOwnerFilter reports whether a and b select the same pods.
+func sameOwnerFilter(a, b *ownerFilter) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.kind == b.kind && a.name == b.name
+}
+
+// watchedNamespaces returns the monitored namespaces, empty for all.
+func (pm *PodMonitor) watchedNamespaces() []string {
+	pm.scopeMu.RLock()
+	defer pm.scopeMu.RUnlock()
+	return pm.namespaces
+}
+
+// podWatchers returns the current pod watchers.
+func (pm *PodMonitor) podWatchers() []*podWatcher {
+	pm.scopeMu.RLock()
+	defer pm.scopeMu.RUnlock()
+	return pm.watchers
+}
+
+// changedSettings names the exported Config fields that differ.
+func changedSettings(old, updated Config) []string {
+	var changed []string
+	oldValue, updatedValue := reflect.ValueOf(old), reflect.ValueOf(updated)
+	for i := 0; i < oldValue.NumField(); i++ {
+		field := oldValue.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), updatedValue.Field(i).Interface()) {
+			changed = append(changed, field.Name)
+		}
+	}
+	return changed
+}
+
+// reload re-reads the configuration on SIGHUP. Filters and the log level
+// take effect at once, a new watch scope re-establishes the watches, and any
+// other change is reported as needing a restart. An invalid configuration
+// is rejected as a whole and the running one is kept.
+func (pm *PodMonitor) reload() {
+	if pm.config.ConfigFile == "" {
+		pm.logger.Println("⚠️  Received SIGHUP but no --config file is set, nothing to reload")
+		return
+	}
+
+	args := pm.config.args
+	if args == nil {
+		args = []string{"--config", pm.config.ConfigFile}
+	}
+	cfg, _, err := parseConfig(args, io.Discard)
+	if err != nil {
+		pm.logger.Printf("❌ Config reload failed, keeping the current settings: %v", err)
+		return
+	}
+
+	filters, err := newEventFilters(cfg)
+	if err != nil {
+		pm.logger.Printf("❌ Config reload failed, keeping the current settings: %v", err)
+		return
+	}
+	level, err := parseLogLevel(cfg.LogLevel)
+	if err != nil {
+		pm.logger.Printf("❌ Config reload failed, keeping the current settings: %v", err)
+		return
+	}
+	scope, err := newWatchScope(cfg)
+	if err != nil {
+		pm.logger.Printf("❌ Config reload failed, keeping the current settings: %v", err)
+		return
+	}
+
+	applied := pm.config
+	appliedValue, cfgValue := reflect.ValueOf(&applied).Elem(), reflect.ValueOf(cfg)
+	var hot, rewatch, restart []string
+	for _, name := range changedSettings(pm.config, cfg) {
+		switch {
+		case hotReloadSettings[name]:
+			hot = append(hot, name)
+		case rewatchSettings[name]:
+			rewatch = append(rewatch, name)
+		default:
+			restart = append(restart, name)
+			continue
+		}
+		appliedValue.FieldByName(name).Set(cfgValue.FieldByName(name))
+	}
+	pm.config = applied
+
+	if len(hot) == 0 && len(rewatch) == 0 && len(restart) == 0 {
+		pm.logger.Printf("🔄 Reloaded %s: no changes", cfg.ConfigFile)
+		return
+	}
+
+	pm.filters.Store(filters)
+	pm.logLevel.Set(level)
+	if len(hot) > 0 {
+		pm.logger.Printf("🔄 Reloaded %s: applied %s", cfg.ConfigFile, strings.Join(hot, ", "))
+	}
+	if len(rewatch) > 0 {
+		pm.logger.Printf("🔄 Reloaded %s: %s changed, re-establishing the watches", cfg.ConfigFile, strings.Join(rewatch, ", "))
+		// Only the latest scope matters if runWatches has not taken the
+		// previous one yet.
+		scope.config = applied
+		select {
+		case <-pm.rescope:
+		default:
+		}
+		pm.rescope <- scope
+	}
+	if len(restart) > 0 {
+		pm.logger.Printf("⚠️  Reloaded %s: %s changed, restart to apply", cfg.ConfigFile, strings.Join(restart, ", "))
+	}
+}
+
+// runWatches runs the watches until ctx is done or they stop, starting them
+// again whenever a reload changes the watch scope.
+func (pm *PodMonitor) runWatches(ctx context.Context) error {
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- pm.run(runCtx)
+		}()
+
+		select {
+		case err := <-done:
+			cancel()
+			return err
+		case scope := <-pm.rescope:
+			cancel()
+			if err := <-done; err != nil {
+				return err
+			}
+			pm.applyScope(scope)
+			pm.logger.Printf("🔄 Re-establishing watches for namespace: %s", namespaceLabel(scope.namespaces))
+			if err := pm.checkPodAccess(ctx); err != nil {
+				pm.logger.Printf("⚠️  %v, the pod watches will fail until it is granted", err)
+			}
+		}
+	}
+}
+
+// applyScope switches the watchers to a new watch scope. The watches must be
+// stopped. A pod watcher whose namespace stays in scope is kept with its
+// tracked pods, so its relist reports what changed while the watches were
+// down; watchers for added namespaces start empty and list silently.
+func (pm *PodMonitor) applyScope(scope watchScope) {
+	watchers, pvcWatchers, workloadWatchers := pm.buildWatchers(scope.config, scope.namespaces)
+
+	pm.scopeMu.Lock()
+	defer pm.scopeMu.Unlock()
+	filterChanged := scope.fieldSelector != pm.fieldSelector || !sameOwnerFilter(scope.ownerFilter, pm.ownerFilter)
+	namespacesChanged := !reflect.DeepEqual(scope.namespaces, pm.namespaces)
+	kept := make(map[string]*podWatcher, len(pm.watchers))
+	for _, w := range pm.watchers {
+		kept[w.namespace] = w
+	}
+	for i, w := range watchers {
+		if old, ok := kept[w.namespace]; ok {
+			// The watcher across all namespaces applies the namespace list
+			// itself, so a new list changes which pods it sees.
+			old.mu.Lock()
+			old.rescoped = filterChanged || (namespacesChanged && old.namespace == metav1.NamespaceAll)
+			old.mu.Unlock()
+			watchers[i] = old
+			delete(kept, w.namespace)
+		}
+	}
+	for _, w := range kept {
+		w.mu.RLock()
+		podsWatched.Sub(float64(len(w.existingPods)))
+		w.mu.RUnlock()
+	}
+
+	pm.namespaces = scope.namespaces
+	pm.fieldSelector = scope.fieldSelector
+	pm.ownerFilter = scope.ownerFilter
+	pm.watchers = watchers
+	pm.pvcWatchers = pvcWatchers
+	pm.workloadWatchers = workloadWatchers
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// rescheduleWindow is how long the node of a deleted pod is remembered so
+// that a replacement with the same name and owner, such as a StatefulSet pod,
+// can be reported as rescheduled.
+const rescheduleWindow = 10 * time.Minute
+
+type deletedPlacement struct {
+	nodeName  string
+	owner     types.UID
+	deletedAt time.Time
+}
+
+// rememberPlacement records the node a deleted pod ran on.
+func (w *podWatcher) rememberPlacement(pod *corev1.Pod, now time.Time) {
+	owner := metav1.GetControllerOf(pod)
+	if pod.Spec.NodeName == "" || owner == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key, placement := range w.deletedPlacements {
+		if now.Sub(placement.deletedAt) > rescheduleWindow {
+			delete(w.deletedPlacements, key)
+		}
+	}
+	w.deletedPlacements[pod.Namespace+"/"+pod.Name] = deletedPlacement{
+		nodeName:  pod.Spec.NodeName,
+		owner:     owner.UID,
+		deletedAt: now,
+	}
+}
+
+// rescheduleReason returns a reason when pod replaces a recently deleted pod
+// with the same name and controller that ran on a different node.
+func (w *podWatcher) rescheduleReason(pod *corev1.Pod, now time.Time) string {
+	owner := metav1.GetControllerOf(pod)
+	if pod.Spec.NodeName == "" || owner == nil {
+		return ""
+	}
+
+	key := pod.Namespace + "/" + pod.Name
+	w.mu.Lock()
+	placement, ok := w.deletedPlacements[key]
+	delete(w.deletedPlacements, key)
+	w.mu.Unlock()
+
+	if !ok || placement.owner != owner.UID || placement.nodeName == pod.Spec.NodeName ||
+		now.Sub(placement.deletedAt) > rescheduleWindow {
+		return ""
+	}
+	reason := fmt.Sprintf("Pod rescheduled from node %s to node %s", placement.nodeName, pod.Spec.NodeName)
+	if w.pm.nodeNotReady(placement.nodeName) {
+		reason += fmt.Sprintf(" (node %s is NotReady)", placement.nodeName)
+	}
+	return reason
+}
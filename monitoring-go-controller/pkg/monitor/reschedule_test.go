@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetChangeReasonNodeAssignment(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{name: "initial schedule", old: "", new: "node-1", want: "Pod scheduled to node node-1"},
+		{name: "in-place reschedule", old: "node-1", new: "node-2", want: "Pod rescheduled from node node-1 to node node-2"},
+		{name: "same node", old: "node-1", new: "node-1", want: genericChangeReason},
+	}
+	pm, _ := newTestMonitor(t, "default")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldPod, newPod := testPod("default", "web-0"), testPod("default", "web-0")
+			oldPod.Spec.NodeName = tt.old
+			newPod.Spec.NodeName = tt.new
+			if got := pm.getChangeReason(oldPod, newPod); got != tt.want {
+				t.Errorf("reason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ownedPod is a pod named name on node, controlled by the StatefulSet with
+// the given UID.
+func ownedPod(name, node string, owner types.UID) *corev1.Pod {
+	pod := testPod("default", name)
+	pod.Spec.NodeName = node
+	if owner != "" {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "web", UID: owner, Controller: &controller}}
+	}
+	return pod
+}
+
+func TestRescheduleReason(t *testing.T) {
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		replacement *corev1.Pod
+		after       time.Duration
+		want        string
+	}{
+		{name: "recreated on another node", replacement: ownedPod("web-0", "node-2", "sts-uid"), after: time.Minute,
+			want: "Pod rescheduled from node node-1 to node node-2"},
+		{name: "recreated on the same node", replacement: ownedPod("web-0", "node-1", "sts-uid"), after: time.Minute},
+		{name: "different owner", replacement: ownedPod("web-0", "node-2", "other-uid"), after: time.Minute},
+		{name: "different name", replacement: ownedPod("web-1", "node-2", "sts-uid"), after: time.Minute},
+		{name: "no controller", replacement: ownedPod("web-0", "node-2", ""), after: time.Minute},
+		{name: "not scheduled yet", replacement: ownedPod("web-0", "", "sts-uid"), after: time.Minute},
+		{name: "outside the window", replacement: ownedPod("web-0", "node-2", "sts-uid"), after: rescheduleWindow + time.Second},
+	}
+	pm, _ := newTestMonitor(t, "default")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newPodWatcher(pm, "default")
+			w.rememberPlacement(ownedPod("web-0", "node-1", "sts-uid"), deletedAt)
+
+			if got := w.rescheduleReason(tt.replacement, deletedAt.Add(tt.after)); got != tt.want {
+				t.Errorf("reason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecreatedPodIsReportedAsRescheduled(t *testing.T) {
+	old := ownedPod("web-0", "node-1", "sts-uid")
+	pm, client := newTestMonitor(t, "default", old)
+	events := startWatching(t, pm)
+	pods := client.CoreV1().Pods("default")
+
+	if err := pods.Delete(context.Background(), "web-0", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	nextEvent(t, events, "DELETED")
+
+	replacement := ownedPod("web-0", "node-2", "sts-uid")
+	replacement.UID = "web-0-new-uid"
+	if _, err := pods.Create(context.Background(), replacement, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if added := nextEvent(t, events, "ADDED"); added.Reason != "Pod rescheduled from node node-1 to node node-2" {
+		t.Errorf("ADDED reason = %q, want the reschedule", added.Reason)
+	}
+}
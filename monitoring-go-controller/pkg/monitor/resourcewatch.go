@@ -0,0 +1,194 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// errRelistDue is returned by resourceWatch.consume when the periodic relist
+// is due.
+var errRelistDue = errors.New("periodic relist due")
+
+// resourceWatch runs the list, watch and reconnect cycle for one resource
+// type. The resource type supplies how to list and watch, how to handle an
+// event (including working out why the object changed) and how long to back
+// off; resourceWatch takes care of resuming from the last resourceVersion and
+// relisting once it has expired.
+type resourceWatch struct {
+	logger *log.Logger
+
+	// list records the current objects and returns the list's
+	// resourceVersion.
+	list func(ctx context.Context) (string, error)
+	// watch opens a watch from resourceVersion.
+	watch func(ctx context.Context, resourceVersion string) (watch.Interface, error)
+	// handle processes one event and returns false when the object is not of
+	// the expected type.
+	handle func(eventType watch.EventType, obj runtime.Object) bool
+	// backoff waits before a reconnect; an error ends the loop.
+	backoff func(ctx context.Context) error
+
+	// Optional hooks. onReconnect runs each time the watch is set up again
+	// after the first attempt, whatever ended the previous one.
+	onEvent     func()
+	onWatching  func(watching bool)
+	onReconnect func()
+
+	// stopCh ends the loop with errStopped when closed; nil never fires.
+	stopCh <-chan struct{}
+
+	// relistInterval, when positive, closes the watch and lists again at
+	// this interval so list reconciles state the watch may have missed.
+	relistInterval time.Duration
+
+	messages watchMessages
+}
+
+// watchMessages are the log lines of a resourceWatch. Each takes a single
+// argument: the error, event object or object for the %v/%T verb.
+type watchMessages struct {
+	listFailed  string
+	watchFailed string
+	expired     string
+	watchError  string
+	unexpected  string
+	cancelled   string
+	stopped     string
+}
+
+// run lists once and then keeps a watch open until ctx is done, stopCh is
+// closed or backoff gives up.
+func (r *resourceWatch) run(ctx context.Context) error {
+	resourceVersion := ""
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && r.onReconnect != nil {
+			r.onReconnect()
+		}
+
+		if resourceVersion == "" {
+			rv, err := r.list(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				r.logger.Printf(r.messages.listFailed, err)
+				if err := r.backoff(ctx); err != nil {
+					return err
+				}
+				continue
+			}
+			resourceVersion = rv
+		}
+
+		watcher, err := r.watch(ctx, resourceVersion)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+				resourceVersion = ""
+			}
+			r.logger.Printf(r.messages.watchFailed, err)
+			if err := r.backoff(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		r.watching(true)
+		resourceVersion, err = r.consume(ctx, watcher, resourceVersion)
+		watcher.Stop()
+		r.watching(false)
+
+		switch {
+		case errors.Is(err, errRelistDue):
+			resourceVersion = ""
+			continue
+		case errors.Is(err, errWatchExpired):
+			r.logger.Print(r.messages.expired)
+			resourceVersion = ""
+		case err != nil:
+			return err
+		case ctx.Err() != nil:
+			return ctx.Err()
+		default:
+			// The API server ends every watch after its timeout; that is
+			// not a failure, so resume at once without backing off.
+			continue
+		}
+
+		if err := r.backoff(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *resourceWatch) watching(watching bool) {
+	if r.onWatching != nil {
+		r.onWatching(watching)
+	}
+}
+
+// consume handles events until the watch closes or the loop is stopped, and
+// returns the last resourceVersion it observed. It returns errWatchExpired
+// when the watch reports that resourceVersion as gone, and errRelistDue when
+// relistInterval has passed.
+func (r *resourceWatch) consume(ctx context.Context, watcher watch.Interface, resourceVersion string) (string, error) {
+	var relist <-chan time.Time
+	if r.relistInterval > 0 {
+		timer := time.NewTimer(r.relistInterval)
+		defer timer.Stop()
+		relist = timer.C
+	}
+
+	for {
+		select {
+		case <-relist:
+			return resourceVersion, errRelistDue
+
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+
+			if event.Type == watch.Error {
+				if status := apierrors.FromObject(event.Object); apierrors.IsResourceExpired(status) || apierrors.IsGone(status) {
+					return resourceVersion, errWatchExpired
+				}
+				r.logger.Printf(r.messages.watchError, event.Object)
+				continue
+			}
+
+			// Only events that are not errors show the watch is healthy.
+			if r.onEvent != nil {
+				r.onEvent()
+			}
+
+			accessor, err := meta.Accessor(event.Object)
+			if err != nil {
+				r.logger.Printf(r.messages.unexpected, event.Object)
+				continue
+			}
+			if !r.handle(event.Type, event.Object) {
+				r.logger.Printf(r.messages.unexpected, event.Object)
+				continue
+			}
+			resourceVersion = accessor.GetResourceVersion()
+
+		case <-ctx.Done():
+			r.logger.Println(r.messages.cancelled)
+			return resourceVersion, ctx.Err()
+
+		case <-r.stopCh:
+			r.logger.Println(r.messages.stopped)
+			return resourceVersion, errStopped
+		}
+	}
+}
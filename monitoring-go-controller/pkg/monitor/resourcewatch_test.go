@@ -0,0 +1,190 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// scriptedWatch is a resourceWatch over fake watches the test drives. Each
+// watch call fails with the next of watchErrs, if any are left, or opens a
+// new watch.FakeWatcher and sends it on opened.
+type scriptedWatch struct {
+	mu          sync.Mutex
+	lists       int
+	watchedFrom []string
+	backoffs    int
+	reconnects  int
+	events      int
+	handled     []watch.EventType
+	watchErrs   []error
+
+	opened chan *watch.FakeWatcher
+}
+
+func newScriptedWatch(t *testing.T, watchErrs ...error) *scriptedWatch {
+	t.Helper()
+	s := &scriptedWatch{opened: make(chan *watch.FakeWatcher, 10), watchErrs: watchErrs}
+	r := &resourceWatch{
+		logger: log.New(io.Discard, "", 0),
+		list: func(ctx context.Context) (string, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.lists++
+			return "10", nil
+		},
+		watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			s.mu.Lock()
+			s.watchedFrom = append(s.watchedFrom, resourceVersion)
+			if len(s.watchErrs) > 0 {
+				err := s.watchErrs[0]
+				s.watchErrs = s.watchErrs[1:]
+				s.mu.Unlock()
+				return nil, err
+			}
+			s.mu.Unlock()
+			w := watch.NewFake()
+			s.opened <- w
+			return w, nil
+		},
+		handle: func(eventType watch.EventType, obj runtime.Object) bool {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.handled = append(s.handled, eventType)
+			return true
+		},
+		backoff: func(ctx context.Context) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.backoffs++
+			return nil
+		},
+		onEvent: func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.events++
+		},
+		onReconnect: func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.reconnects++
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- r.run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("run returned %v, want context.Canceled", err)
+		}
+	})
+	return s
+}
+
+func (s *scriptedWatch) next(t *testing.T) *watch.FakeWatcher {
+	t.Helper()
+	var w *watch.FakeWatcher
+	waitFor(t, "a watch to open", func() bool {
+		select {
+		case w = <-s.opened:
+			return true
+		default:
+			return false
+		}
+	})
+	return w
+}
+
+func podAt(resourceVersion string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: resourceVersion}}
+}
+
+func TestResourceWatchReopensClosedWatchImmediately(t *testing.T) {
+	s := newScriptedWatch(t)
+
+	first := s.next(t)
+	first.Add(podAt("11"))
+	// A clean close, as after the API server's watch timeout.
+	first.Stop()
+	second := s.next(t)
+	second.Modify(podAt("12"))
+	waitFor(t, "the event on the reopened watch", func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.handled) == 2
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lists != 1 {
+		t.Errorf("listed %d times, want 1", s.lists)
+	}
+	if len(s.watchedFrom) != 2 || s.watchedFrom[0] != "10" || s.watchedFrom[1] != "11" {
+		t.Errorf("watched from %q, want [10 11]", s.watchedFrom)
+	}
+	if s.backoffs != 0 || s.reconnects != 1 {
+		t.Errorf("clean close backed off %d times and counted %d reconnects, want 0 and 1", s.backoffs, s.reconnects)
+	}
+}
+
+func TestResourceWatchErrorEventIsNotProgress(t *testing.T) {
+	s := newScriptedWatch(t)
+
+	w := s.next(t)
+	w.Error(&metav1.Status{Status: metav1.StatusFailure, Code: 500, Reason: metav1.StatusReasonInternalError, Message: "etcd unavailable"})
+	w.Add(podAt("11"))
+	waitFor(t, "the pod event", func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.handled) == 1
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.events != 1 {
+		t.Errorf("onEvent ran %d times, want 1 (only for the pod event)", s.events)
+	}
+}
+
+func TestResourceWatchRelistsAfterExpiredWatch(t *testing.T) {
+	s := newScriptedWatch(t)
+
+	w := s.next(t)
+	w.Error(&metav1.Status{Status: metav1.StatusFailure, Code: 410, Reason: metav1.StatusReasonExpired, Message: "too old resource version"})
+	s.next(t)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lists != 2 || s.backoffs != 1 || s.reconnects != 1 || s.events != 0 {
+		t.Errorf("after expiry: lists=%d backoffs=%d reconnects=%d events=%d, want 2, 1, 1 and 0",
+			s.lists, s.backoffs, s.reconnects, s.events)
+	}
+}
+
+func TestResourceWatchCountsReconnectAfterWatchFailure(t *testing.T) {
+	s := newScriptedWatch(t, errors.New("connection refused"))
+
+	s.next(t)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lists != 1 || s.backoffs != 1 || s.reconnects != 1 {
+		t.Errorf("after a failed watch: lists=%d backoffs=%d reconnects=%d, want 1, 1 and 1",
+			s.lists, s.backoffs, s.reconnects)
+	}
+	if len(s.watchedFrom) != 2 || s.watchedFrom[1] != "10" {
+		t.Errorf("watched from %q, want the retry to resume from 10", s.watchedFrom)
+	}
+}
@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// restartThresholdEvents returns a RESTART_THRESHOLD event for each container
+// whose restart count went past restartAlertThreshold in this update. Restart
+// counts only grow for the lifetime of a pod, so comparing with the previous
+// copy fires once per container, and a recreated pod (new UID) starts over.
+func (pm *PodMonitor) restartThresholdEvents(oldPod, pod *corev1.Pod) []PodEvent {
+	threshold := pm.restartAlertThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	oldCounts := make(map[string]int32, len(oldPod.Status.ContainerStatuses))
+	for _, status := range oldPod.Status.ContainerStatuses {
+		oldCounts[status.Name] = status.RestartCount
+	}
+
+	var events []PodEvent
+	for _, status := range pod.Status.ContainerStatuses {
+		oldCount, known := oldCounts[status.Name]
+		if !known || oldCount > threshold || status.RestartCount <= threshold {
+			continue
+		}
+
+		event := pm.newPodEvent("RESTART_THRESHOLD", pod)
+		event.Message = "Restart threshold exceeded"
+		event.Reason = fmt.Sprintf("Container %s exceeded %d restarts (restarts=%d)", status.Name, threshold, status.RestartCount)
+		event.Severity = severityCritical
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,145 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DirectAuth points the monitor at an API server directly, bypassing the
+// in-cluster config and kubeconfig. It is used when any option is set.
+type DirectAuth struct {
+	// APIServer is the API server URL, e.g. https://10.0.0.1:6443.
+	APIServer string
+	// Token is a bearer token. ClientCert and ClientKey are PEM files for
+	// client certificate authentication instead.
+	Token      string
+	ClientCert string
+	ClientKey  string
+	// CACert is a PEM file used to verify the API server. Empty uses the
+	// system roots.
+	CACert string
+}
+
+// set reports whether any direct auth option was given.
+func (a DirectAuth) set() bool {
+	return a.APIServer != "" || a.Token != "" || a.ClientCert != "" || a.ClientKey != "" || a.CACert != ""
+}
+
+// restConfig builds a client config from the options, checking that they
+// are complete: an https API server and either a token or a client
+// certificate and key.
+func (a DirectAuth) restConfig() (*rest.Config, error) {
+	server := strings.TrimSpace(a.APIServer)
+	if server == "" {
+		return nil, errors.New("--api-server is required with --token, --client-cert, --client-key or --ca-cert")
+	}
+	if !strings.HasPrefix(server, "https://") {
+		return nil, fmt.Errorf("invalid --api-server %q: must start with https://", server)
+	}
+
+	token := strings.TrimSpace(a.Token)
+	hasCert := a.ClientCert != "" || a.ClientKey != ""
+	switch {
+	case token == "" && !hasCert:
+		return nil, errors.New("--api-server needs credentials: set --token, or --client-cert and --client-key")
+	case token != "" && hasCert:
+		return nil, errors.New("set either --token or --client-cert/--client-key, not both")
+	case hasCert && (a.ClientCert == "" || a.ClientKey == ""):
+		return nil, errors.New("--client-cert and --client-key must be set together")
+	}
+
+	for flag, path := range map[string]string{"ca-cert": a.CACert, "client-cert": a.ClientCert, "client-key": a.ClientKey} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("invalid --%s: %v", flag, err)
+		}
+	}
+
+	return &rest.Config{
+		Host:        server,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAFile:   a.CACert,
+			CertFile: a.ClientCert,
+			KeyFile:  a.ClientKey,
+		},
+	}, nil
+}
+
+// kubeRESTConfig returns the client config for cfg: the direct auth options
+// when given, else the in-cluster config, else the kubeconfig file.
+func kubeRESTConfig(cfg Config) (*rest.Config, error) {
+	if cfg.DirectAuth.set() {
+		return cfg.DirectAuth.restConfig()
+	}
+
+	// Try in-cluster config first (for when running inside Kubernetes)
+	config, err := rest.InClusterConfig()
+	if err == nil {
+		return config, nil
+	}
+
+	// Fallback to kubeconfig file
+	kubeconfig := cfg.Kubeconfig
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("HOME") + "/.kube/config"
+	}
+	config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes config: %v", err)
+	}
+	return config, nil
+}
+
+// kubeClientConfig returns kubeRESTConfig with the client rate limits and
+// impersonation of cfg applied.
+func kubeClientConfig(cfg Config) (*rest.Config, error) {
+	config, err := kubeRESTConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.KubeQPS <= 0 || cfg.KubeBurst < 1 {
+		return nil, fmt.Errorf("kube QPS must be positive and burst at least 1, got %v and %d", cfg.KubeQPS, cfg.KubeBurst)
+	}
+	config.QPS = cfg.KubeQPS
+	config.Burst = cfg.KubeBurst
+
+	config.Impersonate, err = impersonationConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// impersonationConfig returns the identity --as and --as-group make API
+// requests as. It is empty when --as is unset.
+func impersonationConfig(cfg Config) (rest.ImpersonationConfig, error) {
+	user := strings.TrimSpace(cfg.ImpersonateUser)
+	if user == "" {
+		if len(cfg.ImpersonateGroups) > 0 {
+			return rest.ImpersonationConfig{}, errors.New("--as-group requires --as")
+		}
+		return rest.ImpersonationConfig{}, nil
+	}
+	return rest.ImpersonationConfig{UserName: user, Groups: cfg.ImpersonateGroups}, nil
+}
+
+// explainImpersonation turns the 403 the API server returns when the
+// monitor's own identity may not impersonate user into an error saying which
+// permission is missing. Other errors are returned unchanged.
+func explainImpersonation(err error, user string) error {
+	if user == "" || !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), "cannot impersonate") {
+		return err
+	}
+	return fmt.Errorf("not allowed to impersonate %q: grant the monitor's own identity the impersonate verb with a ClusterRole rule such as "+
+		`{apiGroups: [""], resources: ["users", "groups", "serviceaccounts"], verbs: ["impersonate"]}`+": %v", user, err)
+}
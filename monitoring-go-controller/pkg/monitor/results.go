@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerResult is how one container of a finished pod terminated, telling
+// a clean exit 0 apart from, say, a segfault (signal 11, exit code 139).
+type ContainerResult struct {
+	Name       string    `json:"name"`
+	Init       bool      `json:"init,omitempty"`
+	ExitCode   int32     `json:"exit_code"`
+	Signal     int32     `json:"signal,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// containerResults returns the terminated state of each init and regular
+// container once the pod has reached Succeeded or Failed, and nil before.
+// Containers that never ran, and so have no terminated state, are skipped.
+func containerResults(pod *corev1.Pod) []ContainerResult {
+	if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		return nil
+	}
+
+	var results []ContainerResult
+	add := func(statuses []corev1.ContainerStatus, init bool) {
+		for _, status := range statuses {
+			terminated := status.State.Terminated
+			if terminated == nil {
+				continue
+			}
+			results = append(results, ContainerResult{
+				Name:       status.Name,
+				Init:       init,
+				ExitCode:   terminated.ExitCode,
+				Signal:     terminated.Signal,
+				Reason:     terminated.Reason,
+				Message:    terminated.Message,
+				FinishedAt: terminated.FinishedAt.Time,
+			})
+		}
+	}
+	add(pod.Status.InitContainerStatuses, true)
+	add(pod.Status.ContainerStatuses, false)
+	return results
+}
@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reportResync periodically re-emits every tracked pod as a SYNC event, so a
+// consumer that restarted can rebuild the current state from the stream.
+func (pm *PodMonitor) reportResync(ctx context.Context) {
+	ticker := time.NewTicker(pm.resyncPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, w := range pm.podWatchers() {
+				for _, event := range w.syncEvents(time.Now()) {
+					pm.logEvent(event)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// syncEvents builds one SYNC event per tracked pod with its current phase.
+// They are built under the lock and emitted by the caller, so the watch
+// loop is not held up by the sinks.
+func (w *podWatcher) syncEvents(now time.Time) []PodEvent {
+	pm := w.pm
+
+	w.mu.RLock()
+	pods := make([]*corev1.Pod, 0, len(w.existingPods))
+	phaseSince := make([]time.Time, 0, len(w.existingPods))
+	for uid, pod := range w.existingPods {
+		pods = append(pods, pod)
+		phaseSince = append(phaseSince, w.phaseSince[uid])
+	}
+	w.mu.RUnlock()
+
+	events := make([]PodEvent, 0, len(pods))
+	for i, pod := range pods {
+		event := pm.newPodEvent("SYNC", pod)
+		event.Message = "Current pod state"
+		if inPhase, ok := pm.elapsedSince(phaseSince[i], now); ok && !phaseSince[i].IsZero() {
+			event.PhaseDurationSeconds = inPhase.Seconds()
+		}
+		events = append(events, event)
+	}
+	return events
+}
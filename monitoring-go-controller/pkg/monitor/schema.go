@@ -0,0 +1,24 @@
+package monitor
+
+import "fmt"
+
+// Event JSON schema versions. A version pins the set of fields and their
+// meaning; adding, renaming or retyping a field requires a new version, which
+// consumers opt into with --schema-version. Version 1 is documented in the
+// README.
+const (
+	schemaVersion1      = 1
+	latestSchemaVersion = schemaVersion1
+)
+
+// parseSchemaVersion validates --schema-version. Zero, e.g. from a Config
+// built by hand, selects version 1.
+func parseSchemaVersion(version int) (int, error) {
+	if version == 0 {
+		return schemaVersion1, nil
+	}
+	if version < schemaVersion1 || version > latestSchemaVersion {
+		return 0, fmt.Errorf("unsupported --schema-version %d: must be between %d and %d", version, schemaVersion1, latestSchemaVersion)
+	}
+	return version, nil
+}
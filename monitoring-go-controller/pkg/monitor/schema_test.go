@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// schemaV1Events are the events pinned by the v1 golden files: one with
+// every field set and one with only the fields that are always present.
+func schemaV1Events() map[string]PodEvent {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	priority := int32(1000)
+	return map[string]PodEvent{
+		"full": {
+			Timestamp:            at,
+			EventType:            "MODIFIED",
+			PodName:              "web-7d4b9c6f8-abcde",
+			Namespace:            "shop",
+			PodIP:                "10.0.0.7",
+			NodeName:             "node-1",
+			Phase:                "Running",
+			Labels:               map[string]string{"app": "web", "tier": "frontend"},
+			Message:              "Pod updated",
+			Reason:               "Container app restart count changed to 3",
+			ProbeType:            "liveness",
+			Counts:               map[string]int{"Running": 3, "Pending": 1},
+			PhaseDurationSeconds: 12.5,
+			StartupSeconds:       4.25,
+			LifetimeSeconds:      3600,
+			KubeEvent:            &KubeEvent{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Count: 4, LastSeen: at},
+			Workload:             "Deployment/web",
+			PVC:                  &PVCEvent{Name: "data", StorageClass: "standard", RequestedSize: "10Gi", Phase: "Bound", Volume: "pvc-123"},
+			ContainerResults:     []ContainerResult{{Name: "app", ExitCode: 137, Signal: 9, Reason: "OOMKilled", Message: "out of memory", FinishedAt: at}},
+			Synthetic:            true,
+			FirstSeen:            true,
+			Important:            true,
+			ServiceAccount:       "web",
+			Zone:                 "eu-west-1a",
+			InstanceType:         "m5.large",
+			Severity:             severityWarning,
+			QOSClass:             "Burstable",
+			PriorityClassName:    "high",
+			Priority:             &priority,
+			Usage:                &ResourceUsage{CPUMillicores: 250, MemoryBytes: 134217728},
+			Config:               map[string]string{"namespace": "shop"},
+			Services:             []string{"web"},
+		},
+		"minimal": {
+			Timestamp: at,
+			EventType: "ADDED",
+			PodName:   "web",
+			Namespace: "shop",
+			Phase:     "Pending",
+			Message:   "New pod created",
+		},
+	}
+}
+
+func TestSchemaV1Golden(t *testing.T) {
+	t.Setenv("TIMEZONE", "UTC")
+	pm, _ := newTestMonitor(t, "shop")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	emitted := pm.Events(ctx)
+
+	for name, event := range schemaV1Events() {
+		t.Run(name, func(t *testing.T) {
+			pm.logEvent(event)
+			got, err := json.MarshalIndent(<-emitted, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", "schema_v1_"+name+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("schema v1 output changed; a change to the event JSON needs a new schema version.\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
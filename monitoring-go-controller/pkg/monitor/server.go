@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// startHTTPServers starts the metrics, health, event history and debug
+// endpoints. Endpoints configured with the same address share one server;
+// the event history and debug state are served next to the metrics, or the
+// health checks when metrics are disabled.
+func (pm *PodMonitor) startHTTPServers(ctx context.Context) {
+	muxes := make(map[string]*http.ServeMux)
+	mux := func(addr string) *http.ServeMux {
+		if muxes[addr] == nil {
+			muxes[addr] = http.NewServeMux()
+		}
+		return muxes[addr]
+	}
+
+	if pm.metricsAddr != "" {
+		handler := promhttp.Handler()
+		if pm.otel != nil {
+			// Exemplars are only exposed in the OpenMetrics format.
+			handler = promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+				promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+		}
+		mux(pm.metricsAddr).Handle("/metrics", handler)
+		pm.logger.Printf("📈 Serving metrics on %s/metrics", pm.metricsAddr)
+	}
+	if pm.healthAddr != "" {
+		m := mux(pm.healthAddr)
+		m.HandleFunc("/healthz", pm.handleHealthz)
+		m.HandleFunc("/readyz", pm.handleReadyz)
+		pm.logger.Printf("💓 Serving health checks on %s/healthz and %s/readyz", pm.healthAddr, pm.healthAddr)
+	}
+
+	apiAddr := pm.metricsAddr
+	if apiAddr == "" {
+		apiAddr = pm.healthAddr
+	}
+	if pm.store != nil || pm.recent != nil {
+		if apiAddr == "" {
+			pm.logger.Println("⚠️  Event history API disabled: neither --metrics-addr nor --health-addr is set")
+		}
+	}
+	if apiAddr != "" && pm.store != nil {
+		mux(apiAddr).HandleFunc("/events", pm.handleEvents)
+		pm.logger.Printf("🗄️  Serving event history on %s/events", apiAddr)
+	}
+	if apiAddr != "" && pm.recent != nil {
+		mux(apiAddr).HandleFunc("/events/recent", pm.handleRecentEvents)
+		pm.logger.Printf("🗄️  Serving recent events on %s/events/recent", apiAddr)
+	}
+
+	if apiAddr != "" {
+		mux(apiAddr).HandleFunc("/debug/state", pm.handleDebugState)
+		pm.logger.Printf("🐞 Serving tracked pod state on %s/debug/state", apiAddr)
+	}
+	if apiAddr != "" && len(pm.asyncSinks) > 0 {
+		mux(apiAddr).HandleFunc("/flush", pm.handleFlush)
+		pm.logger.Printf("🚿 Serving sink flushes on %s/flush", apiAddr)
+	}
+
+	for addr, m := range muxes {
+		go pm.serveHTTP(ctx, addr, m)
+	}
+}
+
+// serveHTTP serves handler on addr until ctx is cancelled.
+func (pm *PodMonitor) serveHTTP(ctx context.Context, addr string, handler http.Handler) {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		pm.logger.Printf("❌ HTTP server on %s failed: %v", addr, err)
+	}
+}
@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// cachedService is the part of a Service that decides whether a pod is one
+// of its endpoints.
+type cachedService struct {
+	name     string
+	selector labels.Selector
+	// publishNotReady keeps not-ready pods in the endpoints.
+	publishNotReady bool
+}
+
+// serviceCache keeps the selectors of the Services in the watched namespaces
+// so readiness changes can be tied to the Services they affect without a
+// lookup per event. It is rebuilt from a full Service list periodically.
+type serviceCache struct {
+	clientset  kubernetes.Interface
+	namespaces func() []string
+	interval   time.Duration
+	logger     *log.Logger
+
+	mu       sync.RWMutex
+	services map[string][]cachedService
+}
+
+func newServiceCache(clientset kubernetes.Interface, namespaces func() []string, interval time.Duration, logger *log.Logger) *serviceCache {
+	return &serviceCache{
+		clientset:  clientset,
+		namespaces: namespaces,
+		interval:   interval,
+		logger:     logger,
+		services:   make(map[string][]cachedService),
+	}
+}
+
+func (c *serviceCache) run(ctx context.Context) {
+	if err := c.refresh(ctx); err != nil {
+		c.logger.Printf("⚠️  Failed to load services: %v", err)
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.refresh(ctx); err != nil {
+				c.logger.Printf("⚠️  Failed to refresh services: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *serviceCache) refresh(ctx context.Context) error {
+	listCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	namespaces := c.namespaces()
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	services := make(map[string][]cachedService)
+	for _, namespace := range namespaces {
+		list, err := c.clientset.CoreV1().Services(namespace).List(listCtx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			service := &list.Items[i]
+			// Services without a selector have their endpoints managed by
+			// hand, so pod readiness says nothing about them.
+			if len(service.Spec.Selector) == 0 {
+				continue
+			}
+			services[service.Namespace] = append(services[service.Namespace], cachedService{
+				name:            service.Name,
+				selector:        labels.SelectorFromSet(service.Spec.Selector),
+				publishNotReady: service.Spec.PublishNotReadyAddresses,
+			})
+		}
+	}
+
+	c.mu.Lock()
+	c.services = services
+	c.mu.Unlock()
+	return nil
+}
+
+// matching returns the cached Services whose selector matches the pod.
+func (c *serviceCache) matching(pod *corev1.Pod) []cachedService {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var matched []cachedService
+	podLabels := labels.Set(pod.Labels)
+	for _, service := range c.services[pod.Namespace] {
+		if service.selector.Matches(podLabels) {
+			matched = append(matched, service)
+		}
+	}
+	return matched
+}
+
+// readinessChange reports the Services selecting a pod whose Ready condition
+// changed between oldPod and pod, and notes the ones it was added to or
+// removed from as a ready endpoint. It returns nil when readiness did not
+// change or no Service selects the pod.
+func (c *serviceCache) readinessChange(oldPod, pod *corev1.Pod) ([]string, string) {
+	wasReady, ready := podReady(oldPod) != nil, podReady(pod) != nil
+	if wasReady == ready {
+		return nil, ""
+	}
+
+	matched := c.matching(pod)
+	if len(matched) == 0 {
+		return nil, ""
+	}
+
+	names := make([]string, 0, len(matched))
+	var affected []string
+	for _, service := range matched {
+		names = append(names, service.name)
+		if !service.publishNotReady {
+			affected = append(affected, service.name)
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(affected)
+
+	if len(affected) == 0 {
+		return names, ""
+	}
+	if ready {
+		return names, "added to endpoints of Service " + strings.Join(affected, ", ")
+	}
+	return names, "removed from endpoints of Service " + strings.Join(affected, ", ")
+}
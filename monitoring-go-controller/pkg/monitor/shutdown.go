@@ -0,0 +1,41 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+)
+
+// flushSinks closes every asynchronous sink in parallel once the watchers have
+// stopped, so that events still buffered at shutdown are delivered. All sinks
+// share the same timeout; whatever is still queued when it expires is
+// dropped and counted in the log line.
+func (pm *PodMonitor) flushSinks(timeout time.Duration) {
+	if len(pm.asyncSinks) == 0 {
+		return
+	}
+
+	queued := 0
+	for _, sink := range pm.asyncSinks {
+		queued += sink.pending()
+	}
+
+	var wg sync.WaitGroup
+	for _, sink := range pm.asyncSinks {
+		wg.Add(1)
+		go func(sink asyncSink) {
+			defer wg.Done()
+			sink.Close(timeout)
+		}(sink)
+	}
+	wg.Wait()
+
+	dropped := 0
+	for _, sink := range pm.asyncSinks {
+		dropped += sink.pending()
+	}
+	if dropped > 0 {
+		pm.logger.Printf("⚠️  Flushed %d queued events to sinks, dropped %d after %v", queued-dropped, dropped, timeout)
+		return
+	}
+	pm.logger.Printf("🚿 Flushed %d queued events to sinks", queued)
+}
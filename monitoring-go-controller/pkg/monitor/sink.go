@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// EventSink receives every event that passes the monitor's filters. Emit is
+// called from the watch loop, so implementations that do I/O should buffer
+// and deliver from their own goroutine rather than block.
+type EventSink interface {
+	Emit(event PodEvent) error
+}
+
+// AddSink registers an additional sink. It must be called before Start.
+func (pm *PodMonitor) AddSink(sink EventSink) {
+	pm.sinks = append(pm.sinks, sink)
+}
+
+// LogSink writes each event as a JSON line followed by a human-readable line.
+// It is the default sink and can be turned off with LOG_EVENTS=false.
+type LogSink struct {
+	logger *log.Logger
+	// color colors the human-readable line by event type and severity.
+	color bool
+}
+
+// NewLogSink returns a LogSink writing to logger.
+func NewLogSink(logger *log.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) printf(color, format string, args ...any) {
+	s.logger.Print(colorize(color, fmt.Sprintf(format, args...)))
+}
+
+func (s *LogSink) Emit(event PodEvent) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event to JSON: %v", err)
+	}
+	s.logger.Printf("%s", string(eventJSON))
+
+	// Also log in human-readable format
+	color := ""
+	if s.color {
+		color = eventColor(event)
+	}
+	switch event.EventType {
+	case "ADDED":
+		s.printf(color, "🆕 NEW POD CREATED: %s in namespace %s (Phase: %s, Node: %s)",
+			event.PodName, event.Namespace, event.Phase, event.NodeName)
+	case "DELETED":
+		if event.LifetimeSeconds > 0 {
+			s.printf(color, "🗑️  POD DELETED: %s in namespace %s (lived %s)",
+				event.PodName, event.Namespace, time.Duration(event.LifetimeSeconds*float64(time.Second)).Round(time.Second))
+		} else {
+			s.printf(color, "🗑️  POD DELETED: %s in namespace %s",
+				event.PodName, event.Namespace)
+		}
+	case "MODIFIED":
+		s.printf(color, "🔄 POD UPDATED: %s in namespace %s (Phase: %s, Reason: %s)",
+			event.PodName, event.Namespace, event.Phase, event.Reason)
+	case "SYNC":
+		s.printf(color, "🔃 POD SYNC: %s in namespace %s (Phase: %s)",
+			event.PodName, event.Namespace, event.Phase)
+	case "NS_POD_COUNTS":
+		s.printf(color, "📊 POD COUNTS: namespace %s (%s)",
+			event.Namespace, formatCounts(event.Counts))
+	case "STATEFULSET_ROLLOUT", "DAEMONSET_ROLLOUT":
+		s.printf(color, "🚢 ROLLOUT: %s in namespace %s (%s)",
+			event.Workload, event.Namespace, event.Reason)
+	case "PVC_PENDING", "PVC_BOUND", "PVC_LOST":
+		if event.PVC != nil {
+			s.printf(color, "💾 %s: %s in namespace %s (%s)",
+				strings.ReplaceAll(event.EventType, "_", " "), event.PVC.Name, event.Namespace, event.Reason)
+		}
+	case "HEALTH_SUMMARY":
+		s.printf(color, "📋 HEALTH SUMMARY: namespace %s, %s (%s)",
+			event.Namespace, event.Message, formatCounts(event.Counts))
+		if event.Reason != "" {
+			s.printf(color, "📋 %s", event.Reason)
+		}
+	case "TERMINAL_LINGER":
+		s.printf(color, "🪦 TERMINAL POD LINGERING: %s in namespace %s (%s)",
+			event.PodName, event.Namespace, event.Reason)
+	case "USAGE":
+		if event.Usage != nil {
+			s.printf(color, "📈 USAGE: %s in namespace %s (CPU: %dm, Memory: %dMi)",
+				event.PodName, event.Namespace, event.Usage.CPUMillicores, event.Usage.MemoryBytes/(1024*1024))
+		}
+	case "MONITOR_STARTED":
+		s.printf(color, "🟢 MONITOR STARTED: version %s watching namespace %s (sinks: %s)",
+			event.Config["version"], event.Config["namespace"], event.Config["sinks"])
+	case "MONITOR_STOPPED":
+		s.printf(color, "🔴 MONITOR STOPPED: namespace %s", event.Config["namespace"])
+	case "POD_PENDING":
+		s.printf(color, "⏳ POD STUCK PENDING: %s in namespace %s (%s)",
+			event.PodName, event.Namespace, event.Reason)
+	case "TERMINATING_STUCK":
+		s.printf(color, "🧟 POD STUCK TERMINATING: %s in namespace %s (%s)",
+			event.PodName, event.Namespace, event.Reason)
+	case "RESTART_THRESHOLD":
+		s.printf(color, "🚨 RESTART THRESHOLD: %s in namespace %s (%s)",
+			event.PodName, event.Namespace, event.Reason)
+	case "POD_FLAPPING":
+		s.printf(color, "🔁 POD FLAPPING: %s in namespace %s (%s)",
+			event.PodName, event.Namespace, event.Reason)
+	case "NODE_NOT_READY", "NODE_READY", "NODE_CORDONED", "NODE_UNCORDONED", "NODE_PRESSURE":
+		s.printf(color, "🖥️  %s: node %s (%s; %s)",
+			strings.ReplaceAll(event.EventType, "_", " "), event.NodeName, event.Reason, event.Message)
+	case "PROBE_FAILED":
+		s.printf(color, "🩺 PROBE FAILED: %s in namespace %s (%s probe: %s)",
+			event.PodName, event.Namespace, event.ProbeType, event.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package monitor
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// slackEmoji matches the emoji used for each event type in logEvent's
+// human-readable lines.
+var slackEmoji = map[string]string{
+	"ADDED":               "🆕",
+	"DELETED":             "🗑️",
+	"MODIFIED":            "🔄",
+	"TERMINAL_LINGER":     "🪦",
+	"POD_PENDING":         "⏳",
+	"TERMINATING_STUCK":   "🧟",
+	"POD_FLAPPING":        "🔁",
+	"PROBE_FAILED":        "🩺",
+	"RESTART_THRESHOLD":   "🚨",
+	"HEALTH_SUMMARY":      "📋",
+	"STATEFULSET_ROLLOUT": "🚢",
+	"PVC_PENDING":         "💾",
+	"PVC_LOST":            "💾",
+	"DAEMONSET_ROLLOUT":   "🚢",
+	"NODE_NOT_READY":      "🖥️",
+	"NODE_PRESSURE":       "🖥️",
+}
+
+// slackSink posts warning-level events to a Slack incoming webhook. Posts are
+// limited to maxPerMinute; events over the limit are counted and summarised
+// in the next message instead of flooding the channel.
+type slackSink struct {
+	url          string
+	maxPerMinute int
+	client       *http.Client
+	logger       *log.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	events chan PodEvent
+	done   chan struct{}
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// newSlackSink builds a sink for url, reading SLACK_MAX_PER_MINUTE. It
+// returns nil when url is empty. tlsConfig may be nil.
+func newSlackSink(url string, tlsConfig *tls.Config, logger *log.Logger) (*slackSink, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, fmt.Errorf("invalid Slack webhook URL %q: must start with http:// or https://", url)
+	}
+
+	maxPerMinute := envInt("SLACK_MAX_PER_MINUTE", 10)
+	if maxPerMinute < 1 {
+		maxPerMinute = 1
+	}
+
+	return &slackSink{
+		url:          url,
+		maxPerMinute: maxPerMinute,
+		client:       newHTTPClient(10*time.Second, tlsConfig),
+		logger:       logger,
+		events:       make(chan PodEvent, 100),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// notifyEvent selects the events posted to chat: warning and critical events
+// and health summaries.
+func notifyEvent(event PodEvent) bool {
+	return event.Severity == severityWarning || event.Severity == severityCritical || event.EventType == "HEALTH_SUMMARY"
+}
+
+// Emit queues the events notifyEvent selects without blocking and ignores
+// the rest.
+func (s *slackSink) Emit(event PodEvent) error {
+	if !notifyEvent(event) {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("Slack buffer full")
+	}
+}
+
+// run posts queued events until Close is called, allowing at most
+// maxPerMinute posts in each one-minute window.
+func (s *slackSink) run() {
+	defer close(s.done)
+
+	var windowStart time.Time
+	sent, suppressed := 0, 0
+	for event := range s.events {
+		now := time.Now()
+		if now.Sub(windowStart) >= time.Minute {
+			windowStart = now
+			sent = 0
+		}
+		if sent >= s.maxPerMinute {
+			suppressed++
+			continue
+		}
+
+		text := formatSlackMessage(event)
+		if suppressed > 0 {
+			text += fmt.Sprintf("\n_%d more warnings were not posted because of rate limiting._", suppressed)
+			suppressed = 0
+		}
+		s.post(text)
+		sent++
+	}
+
+	if suppressed > 0 {
+		s.logger.Printf("⚠️  %d Slack warnings were not posted because of rate limiting", suppressed)
+	}
+}
+
+// Close stops accepting events and waits up to timeout for queued posts.
+func (s *slackSink) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		s.logger.Println("⚠️  Timed out posting events to Slack")
+	}
+}
+
+func (s *slackSink) pending() int {
+	return len(s.events)
+}
+
+func formatSlackMessage(event PodEvent) string {
+	emoji := slackEmoji[event.EventType]
+	if emoji == "" {
+		emoji = "⚠️"
+	}
+
+	text := fmt.Sprintf("%s *%s* `%s` in namespace `%s`", emoji, event.EventType, event.PodName, event.Namespace)
+	if event.EventType == "HEALTH_SUMMARY" {
+		text = fmt.Sprintf("%s *%s* namespace `%s`: %s\n%s", emoji, event.EventType, event.Namespace, event.Message, formatCounts(event.Counts))
+	} else if event.PVC != nil {
+		text = fmt.Sprintf("%s *%s* PVC `%s` in namespace `%s`", emoji, event.EventType, event.PVC.Name, event.Namespace)
+	} else if event.Workload != "" {
+		text = fmt.Sprintf("%s *%s* `%s` in namespace `%s`", emoji, event.EventType, event.Workload, event.Namespace)
+	} else if event.PodName == "" {
+		text = fmt.Sprintf("%s *%s* node `%s`", emoji, event.EventType, event.NodeName)
+	} else if event.NodeName != "" {
+		text += fmt.Sprintf(" on node `%s`", event.NodeName)
+	}
+	if event.Reason != "" {
+		text += "\n" + event.Reason
+	}
+	return text
+}
+
+func (s *slackSink) post(text string) {
+	body, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		s.logger.Printf("❌ Failed to marshal Slack message: %v", err)
+		return
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		s.logger.Printf("❌ Failed to post to Slack: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		s.logger.Printf("❌ Slack rejected message: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+}
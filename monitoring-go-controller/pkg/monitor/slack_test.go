@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"testing"
+)
+
+func TestSlackSinkPostsWarnings(t *testing.T) {
+	t.Setenv("SLACK_MAX_PER_MINUTE", "2")
+	server := newHookServer(t)
+	sink, err := newSlackSink(server.URL, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warning := PodEvent{EventType: "POD_PENDING", PodName: "web", Namespace: "shop", NodeName: "node-1",
+		Reason: "Pending for 5m0s", Severity: severityWarning}
+	info := PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop"}
+	deliverAll(t, sink, info, warning, warning, warning)
+
+	requests := server.received()
+	if len(requests) != 2 {
+		t.Fatalf("Slack received %d messages, want 2 (the per-minute limit; informational events are not posted)", len(requests))
+	}
+	var message map[string]string
+	if err := json.Unmarshal(requests[0].body, &message); err != nil {
+		t.Fatalf("message is not JSON: %v\n%s", err, requests[0].body)
+	}
+	want := "⏳ *POD_PENDING* `web` in namespace `shop` on node `node-1`\nPending for 5m0s"
+	if len(message) != 1 || message["text"] != want {
+		t.Errorf("message = %q, want only text %q", message, want)
+	}
+}
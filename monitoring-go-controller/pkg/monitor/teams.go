@@ -0,0 +1,229 @@
+package monitor
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// teamsSink posts the same events as slackSink to a Microsoft Teams incoming
+// webhook as MessageCards, with the same per-minute rate limit.
+type teamsSink struct {
+	url          string
+	maxPerMinute int
+	client       *http.Client
+	logger       *log.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	events chan PodEvent
+	done   chan struct{}
+}
+
+// teamsCard is a legacy actionable MessageCard, which Teams incoming
+// webhooks render without any app registration.
+type teamsCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Title      string         `json:"title"`
+	Text       string         `json:"text,omitempty"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+type teamsSection struct {
+	Facts []teamsFact `json:"facts"`
+}
+
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Card colors by severity; other events, i.e. health summaries, are blue.
+const (
+	teamsColorCritical = "D13438"
+	teamsColorWarning  = "FFA500"
+	teamsColorInfo     = "0078D7"
+)
+
+// newTeamsSink builds a sink for url, reading TEAMS_MAX_PER_MINUTE. It
+// returns nil when url is empty. tlsConfig may be nil.
+func newTeamsSink(url string, tlsConfig *tls.Config, logger *log.Logger) (*teamsSink, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, fmt.Errorf("invalid Teams webhook URL %q: must start with http:// or https://", url)
+	}
+
+	maxPerMinute := envInt("TEAMS_MAX_PER_MINUTE", 10)
+	if maxPerMinute < 1 {
+		maxPerMinute = 1
+	}
+
+	return &teamsSink{
+		url:          url,
+		maxPerMinute: maxPerMinute,
+		client:       newHTTPClient(10*time.Second, tlsConfig),
+		logger:       logger,
+		events:       make(chan PodEvent, 100),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// Emit queues the events notifyEvent selects without blocking and ignores
+// the rest.
+func (s *teamsSink) Emit(event PodEvent) error {
+	if !notifyEvent(event) {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("Teams buffer full")
+	}
+}
+
+// run posts queued events until Close is called, allowing at most
+// maxPerMinute posts in each one-minute window.
+func (s *teamsSink) run() {
+	defer close(s.done)
+
+	var windowStart time.Time
+	sent, suppressed := 0, 0
+	for event := range s.events {
+		now := time.Now()
+		if now.Sub(windowStart) >= time.Minute {
+			windowStart = now
+			sent = 0
+		}
+		if sent >= s.maxPerMinute {
+			suppressed++
+			continue
+		}
+
+		card := formatTeamsCard(event)
+		if suppressed > 0 {
+			card.Text = fmt.Sprintf("_%d more warnings were not posted because of rate limiting._", suppressed)
+			suppressed = 0
+		}
+		s.post(card)
+		sent++
+	}
+
+	if suppressed > 0 {
+		s.logger.Printf("⚠️  %d Teams warnings were not posted because of rate limiting", suppressed)
+	}
+}
+
+// Close stops accepting events and waits up to timeout for queued posts.
+func (s *teamsSink) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		s.logger.Println("⚠️  Timed out posting events to Teams")
+	}
+}
+
+func (s *teamsSink) pending() int {
+	return len(s.events)
+}
+
+// formatTeamsCard lists the event's object, namespace, node, reason and time
+// as facts under a title naming the event type.
+func formatTeamsCard(event PodEvent) teamsCard {
+	emoji := slackEmoji[event.EventType]
+	if emoji == "" {
+		emoji = "⚠️"
+	}
+
+	color := teamsColorInfo
+	switch event.Severity {
+	case severityCritical:
+		color = teamsColorCritical
+	case severityWarning:
+		color = teamsColorWarning
+	}
+
+	var facts []teamsFact
+	addFact := func(name, value string) {
+		if value != "" {
+			facts = append(facts, teamsFact{Name: name, Value: value})
+		}
+	}
+	addFact("Pod", event.PodName)
+	if event.PVC != nil {
+		addFact("PVC", event.PVC.Name)
+	}
+	addFact("Workload", event.Workload)
+	addFact("Namespace", event.Namespace)
+	addFact("Node", event.NodeName)
+	addFact("Severity", event.Severity)
+	if event.EventType == "HEALTH_SUMMARY" {
+		addFact("Summary", event.Message)
+		addFact("Counts", formatCounts(event.Counts))
+	}
+	addFact("Reason", event.Reason)
+	addFact("Time", event.Timestamp.Format(time.RFC3339))
+
+	subject := tableObject(event)
+	if subject == "-" {
+		subject = "namespace " + event.Namespace
+	}
+	return teamsCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: color,
+		Summary:    fmt.Sprintf("%s %s", event.EventType, subject),
+		Title:      fmt.Sprintf("%s %s: %s", emoji, event.EventType, subject),
+		Sections:   []teamsSection{{Facts: facts}},
+	}
+}
+
+func (s *teamsSink) post(card teamsCard) {
+	body, err := json.Marshal(card)
+	if err != nil {
+		s.logger.Printf("❌ Failed to marshal Teams card: %v", err)
+		return
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		s.logger.Printf("❌ Failed to post to Teams: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		s.logger.Printf("❌ Teams rejected card: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+}
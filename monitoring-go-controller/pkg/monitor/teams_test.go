@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTeamsSinkPostsMessageCard(t *testing.T) {
+	server := newHookServer(t)
+	sink, err := newTeamsSink(server.URL, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deliverAll(t, sink, PodEvent{
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		EventType: "MODIFIED",
+		PodName:   "web",
+		Namespace: "shop",
+		NodeName:  "node-1",
+		Reason:    "Container app OOMKilled",
+		Severity:  severityCritical,
+	})
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("Teams received %d requests, want 1", len(requests))
+	}
+	if requests[0].contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", requests[0].contentType)
+	}
+
+	var card struct {
+		Type       string `json:"@type"`
+		Context    string `json:"@context"`
+		ThemeColor string `json:"themeColor"`
+		Summary    string `json:"summary"`
+		Title      string `json:"title"`
+		Sections   []struct {
+			Facts []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"facts"`
+		} `json:"sections"`
+	}
+	if err := json.Unmarshal(requests[0].body, &card); err != nil {
+		t.Fatalf("card is not JSON: %v\n%s", err, requests[0].body)
+	}
+	if card.Type != "MessageCard" || card.Context != "https://schema.org/extensions" {
+		t.Errorf("@type/@context = %q/%q, want a MessageCard", card.Type, card.Context)
+	}
+	if card.ThemeColor != teamsColorCritical {
+		t.Errorf("themeColor = %q, want %q for a critical event", card.ThemeColor, teamsColorCritical)
+	}
+	if card.Summary != "MODIFIED web" || card.Title != "🔄 MODIFIED: web" {
+		t.Errorf("summary/title = %q/%q", card.Summary, card.Title)
+	}
+	if len(card.Sections) != 1 {
+		t.Fatalf("card has %d sections, want 1", len(card.Sections))
+	}
+	facts := make(map[string]string)
+	var names []string
+	for _, fact := range card.Sections[0].Facts {
+		facts[fact.Name] = fact.Value
+		names = append(names, fact.Name)
+	}
+	wantNames := []string{"Pod", "Namespace", "Node", "Severity", "Reason", "Time"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("facts = %q, want %q", names, wantNames)
+	}
+	if facts["Pod"] != "web" || facts["Namespace"] != "shop" || facts["Node"] != "node-1" ||
+		facts["Reason"] != "Container app OOMKilled" || facts["Time"] != "2024-05-01T12:00:00Z" {
+		t.Errorf("facts = %v", facts)
+	}
+}
+
+func TestTeamsSinkRateLimits(t *testing.T) {
+	t.Setenv("TEAMS_MAX_PER_MINUTE", "2")
+	server := newHookServer(t)
+	sink, err := newTeamsSink(server.URL, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warning := PodEvent{EventType: "POD_PENDING", PodName: "web", Namespace: "shop", Severity: severityWarning}
+	info := PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop"}
+	deliverAll(t, sink, info, warning, warning, warning, warning)
+
+	if got := len(server.received()); got != 2 {
+		t.Errorf("Teams received %d cards, want 2 (the per-minute limit; informational events are not posted)", got)
+	}
+}
@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// terminatingReason reports a pod entering Terminating, i.e. the transition
+// to a non-nil DeletionTimestamp.
+func terminatingReason(oldPod, newPod *corev1.Pod) string {
+	if oldPod.DeletionTimestamp != nil || newPod.DeletionTimestamp == nil {
+		return ""
+	}
+	return fmt.Sprintf("Pod terminating (grace period %ds)", gracePeriodSeconds(newPod))
+}
+
+// gracePeriodSeconds returns the pod's termination grace period, defaulting
+// to Kubernetes' 30 seconds when unset.
+func gracePeriodSeconds(pod *corev1.Pod) int64 {
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		return *pod.Spec.TerminationGracePeriodSeconds
+	}
+	return corev1.DefaultTerminationGracePeriodSeconds
+}
+
+// terminatingSince returns when the pod was asked to delete. The API server
+// sets DeletionTimestamp to that time plus the grace period of the delete
+// request; without DeletionGracePeriodSeconds the timestamp is used as is,
+// which only errs on the late side.
+func terminatingSince(pod *corev1.Pod) time.Time {
+	since := pod.DeletionTimestamp.Time
+	if pod.DeletionGracePeriodSeconds != nil {
+		since = since.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+	}
+	return since
+}
+
+// watchStuckTerminating periodically scans tracked pods for ones still
+// Terminating past their grace period, the usual symptom of a finalizer
+// that is never removed or a node that stopped reporting.
+func (pm *PodMonitor) watchStuckTerminating(ctx context.Context) {
+	ticker := time.NewTicker(lingerScanInterval(pm.terminatingSlack))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, w := range pm.podWatchers() {
+				for _, event := range w.findStuckTerminatingPods(time.Now()) {
+					pm.logEvent(event)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// findStuckTerminatingPods returns one TERMINATING_STUCK event for each
+// pod Terminating for longer than its spec.terminationGracePeriodSeconds
+// plus the slack. Each pod is reported only once.
+func (w *podWatcher) findStuckTerminatingPods(now time.Time) []PodEvent {
+	pm := w.pm
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []PodEvent
+	for uid, pod := range w.existingPods {
+		if pod.DeletionTimestamp == nil || w.terminatingReported[uid] {
+			continue
+		}
+
+		gracePeriod := time.Duration(gracePeriodSeconds(pod)) * time.Second
+		overdue, ok := pm.elapsedSince(terminatingSince(pod).Add(gracePeriod), now)
+		if !ok || overdue <= pm.terminatingSlack {
+			continue
+		}
+
+		w.terminatingReported[uid] = true
+		event := pm.newPodEvent("TERMINATING_STUCK", pod)
+		event.Message = "Pod stuck Terminating"
+		event.Reason = fmt.Sprintf("Terminating %v past its %ds grace period",
+			overdue.Round(time.Second), gracePeriodSeconds(pod))
+		if len(pod.Finalizers) > 0 {
+			event.Reason += " (finalizers: " + strings.Join(pod.Finalizers, ", ") + ")"
+		}
+		event.Severity = severityWarning
+		events = append(events, event)
+	}
+	return events
+}
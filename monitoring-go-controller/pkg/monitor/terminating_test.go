@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindStuckTerminatingPodsWaitsForTheGracePeriod(t *testing.T) {
+	deleted := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	grace := int64(120)
+
+	tests := []struct {
+		name  string
+		slack string
+		after time.Duration
+		want  bool
+	}{
+		{name: "within the grace period", after: 90 * time.Second},
+		{name: "past the grace period", after: 121 * time.Second, want: true},
+		{name: "within the slack", slack: "1m", after: 150 * time.Second},
+		{name: "past the slack", slack: "1m", after: 181 * time.Second, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERMINATING_SLACK", tt.slack)
+			pm, _ := newTestMonitor(t, "default")
+			w := newPodWatcher(pm, "default")
+
+			pod := testPod("default", "web")
+			pod.Spec.TerminationGracePeriodSeconds = &grace
+			pod.DeletionGracePeriodSeconds = &grace
+			pod.DeletionTimestamp = &metav1.Time{Time: deleted.Add(time.Duration(grace) * time.Second)}
+			w.trackPod(pod)
+
+			events := w.findStuckTerminatingPods(deleted.Add(tt.after))
+			if got := len(events) == 1; got != tt.want {
+				t.Fatalf("stuck after %v with slack %q: got %d events, want stuck=%v", tt.after, tt.slack, len(events), tt.want)
+			}
+			if tt.want && events[0].EventType != "TERMINATING_STUCK" {
+				t.Errorf("event type = %s, want TERMINATING_STUCK", events[0].EventType)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package monitor
+
+import "time"
+
+// genericChangeReason is what getChangeReason returns when none of the
+// changes it knows about happened.
+const genericChangeReason = "Metadata or spec updated"
+
+type lastModified struct {
+	at     time.Time
+	reason string
+}
+
+// throttled reports whether a MODIFIED event should be dropped because the
+// same pod already emitted one within --modified-throttle and this one adds
+// nothing: either it only carries the generic metadata reason or it repeats
+// the previous reason. Distinct reasons and important pods always get through.
+func (w *podWatcher) throttled(event PodEvent, uid string, now time.Time) bool {
+	window := w.pm.modifiedThrottle
+	if window <= 0 || event.Important {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	last, seen := w.lastModified[uid]
+	if seen && now.Sub(last.at) < window &&
+		(event.Reason == genericChangeReason || event.Reason == last.reason) {
+		return true
+	}
+	w.lastModified[uid] = lastModified{at: now, reason: event.Reason}
+	return false
+}
@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	timeFormatRFC3339   = "rfc3339"
+	timeFormatUnix      = "unix"
+	timeFormatUnixMilli = "unixmilli"
+)
+
+// timestamps controls how event and log timestamps are written: the layout
+// and the time zone they are shown in.
+type timestamps struct {
+	format   string
+	location *time.Location
+}
+
+// parseTimestamps validates format (rfc3339, unix or unixmilli) and loads
+// timezone, an IANA name such as "Europe/Berlin" or "UTC". An empty timezone
+// keeps the local time zone.
+func parseTimestamps(format, timezone string) (timestamps, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "":
+		format = timeFormatRFC3339
+	case timeFormatRFC3339, timeFormatUnix, timeFormatUnixMilli:
+	default:
+		return timestamps{}, fmt.Errorf("invalid time format %q: must be %s, %s or %s",
+			format, timeFormatRFC3339, timeFormatUnix, timeFormatUnixMilli)
+	}
+
+	location := time.Local
+	if timezone = strings.TrimSpace(timezone); timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(timezone); err != nil {
+			return timestamps{}, fmt.Errorf("invalid timezone %q: %v", timezone, err)
+		}
+	}
+	return timestamps{format: format, location: location}, nil
+}
+
+// replaceTimeAttr is a slog ReplaceAttr function rewriting the record time
+// in the configured zone and format.
+func (ts timestamps) replaceTimeAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) > 0 || attr.Key != slog.TimeKey || attr.Value.Kind() != slog.KindTime {
+		return attr
+	}
+	t := attr.Value.Time().In(ts.location)
+	switch ts.format {
+	case timeFormatUnix:
+		return slog.Int64(attr.Key, t.Unix())
+	case timeFormatUnixMilli:
+		return slog.Int64(attr.Key, t.UnixMilli())
+	}
+	return slog.Time(attr.Key, t)
+}
+
+// podEventJSON has PodEvent's fields without its JSON methods.
+type podEventJSON PodEvent
+
+// MarshalJSON writes timestamp as RFC 3339, or as Unix seconds or
+// milliseconds when the event was emitted with that time format. Events
+// emitted with --output-format=cloudevents are wrapped in their envelope.
+func (e PodEvent) MarshalJSON() ([]byte, error) {
+	if e.cloudEvent != nil {
+		return e.marshalCloudEvent()
+	}
+	switch e.timeFormat {
+	case timeFormatUnix:
+		return json.Marshal(struct {
+			Timestamp int64 `json:"timestamp"`
+			podEventJSON
+		}{e.Timestamp.Unix(), podEventJSON(e)})
+	case timeFormatUnixMilli:
+		return json.Marshal(struct {
+			Timestamp int64 `json:"timestamp"`
+			podEventJSON
+		}{e.Timestamp.UnixMilli(), podEventJSON(e)})
+	}
+	return json.Marshal(podEventJSON(e))
+}
+
+// UnmarshalJSON reads back any timestamp MarshalJSON writes. Numbers are
+// taken as Unix milliseconds when they are too large to be seconds.
+func (e *PodEvent) UnmarshalJSON(data []byte) error {
+	decoded := struct {
+		*podEventJSON
+		Timestamp json.RawMessage `json:"timestamp"`
+	}{podEventJSON: (*podEventJSON)(e)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	raw := bytes.TrimSpace(decoded.Timestamp)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	if raw[0] == '"' {
+		return json.Unmarshal(raw, &e.Timestamp)
+	}
+
+	n, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid event timestamp %s: %v", raw, err)
+	}
+	if n > 1e11 {
+		e.Timestamp = time.UnixMilli(n)
+	} else {
+		e.Timestamp = time.Unix(n, 0)
+	}
+	return nil
+}
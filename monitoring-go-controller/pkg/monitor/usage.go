@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceUsage is the current usage of a pod summed over its containers, as
+// reported by metrics-server.
+type ResourceUsage struct {
+	CPUMillicores int64 `json:"cpu_millicores"`
+	MemoryBytes   int64 `json:"memory_bytes"`
+}
+
+// reportUsage periodically queries the metrics.k8s.io API and emits a USAGE
+// event for every tracked pod that has metrics. A missing metrics-server only
+// pauses reporting; it is retried on the next tick.
+func (pm *PodMonitor) reportUsage(ctx context.Context) {
+	ticker := time.NewTicker(pm.usageInterval)
+	defer ticker.Stop()
+
+	available := true
+	for {
+		select {
+		case <-ticker.C:
+			err := pm.emitUsage(ctx)
+			switch {
+			case err == nil:
+				if !available {
+					pm.logger.Println("📈 metrics-server is reachable again, resuming usage reporting")
+				}
+				available = true
+			case apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err):
+				if available {
+					pm.logger.Printf("⚠️  metrics-server not available, usage reporting paused: %v", err)
+				}
+				available = false
+			default:
+				pm.logger.Printf("❌ Failed to fetch pod usage: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (pm *PodMonitor) emitUsage(ctx context.Context) error {
+	for _, w := range pm.podWatchers() {
+		if err := w.emitUsage(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *podWatcher) emitUsage(ctx context.Context) error {
+	pm := w.pm
+
+	listCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	podMetrics, err := pm.metricsClient.MetricsV1beta1().PodMetricses(w.namespace).List(listCtx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var events []PodEvent
+	w.mu.RLock()
+	tracked := make(map[string]string, len(w.existingPods))
+	for uid, pod := range w.existingPods {
+		tracked[pod.Namespace+"/"+pod.Name] = uid
+	}
+	for _, metrics := range podMetrics.Items {
+		uid, ok := tracked[metrics.Namespace+"/"+metrics.Name]
+		if !ok {
+			continue
+		}
+
+		usage := &ResourceUsage{}
+		for _, container := range metrics.Containers {
+			usage.CPUMillicores += container.Usage.Cpu().MilliValue()
+			usage.MemoryBytes += container.Usage.Memory().Value()
+		}
+
+		event := pm.newPodEvent("USAGE", w.existingPods[uid])
+		event.Message = "Resource usage"
+		event.Usage = usage
+		events = append(events, event)
+	}
+	w.mu.RUnlock()
+
+	for _, event := range events {
+		pm.logEvent(event)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// podWatcher watches pods in one namespace, or in all namespaces when
+// namespace is empty, and keeps its own view of the pods it has seen. Each
+// watcher lists, watches and reconnects independently, so a failure in one
+// namespace does not tear down the others.
+type podWatcher struct {
+	pm        *PodMonitor
+	namespace string
+
+	retryCount int
+	// watchingSince is when the watch last opened.
+	watchingSince time.Time
+	// reconnect is the span of the last reconnect backoff until the watch
+	// opens again, so the relist it leads to joins its trace.
+	reconnect trace.SpanContext
+	// resourceVersion is the last one observed, for /debug/state. In
+	// informer mode informerVersion reports it instead. Both guarded by mu.
+	resourceVersion string
+	informerVersion func() string
+	// listed is set once the first list has been tracked; pods new in later
+	// relists were created while the watch was down.
+	listed bool
+	// rescoped is set when a reload changed which pods the watcher sees.
+	// The next relist tracks pods that entered or left the scope silently.
+	// Guarded by mu.
+	rescoped bool
+
+	// ready is true while the initial list has completed and the watch is
+	// open; it is cleared while backing off before a reconnect.
+	ready atomic.Bool
+
+	mu              sync.RWMutex
+	existingPods    map[string]*corev1.Pod
+	phaseSince      map[string]time.Time
+	lingerReported  map[string]bool
+	pendingReported map[string]bool
+	flaps           map[string]*flapState
+	lastModified    map[string]lastModified
+	startupReported map[string]bool
+	kubeEvents      map[string]*KubeEvent
+	// probeFailures holds the latest probe failure per container/probe.
+	probeFailures map[string]map[string]probeFailure
+
+	// terminatingReported marks pods already reported as stuck Terminating.
+	terminatingReported map[string]bool
+
+	// deletedPlacements remembers where recently deleted pods ran, keyed by
+	// namespace/name.
+	deletedPlacements map[string]deletedPlacement
+}
+
+func newPodWatcher(pm *PodMonitor, namespace string) *podWatcher {
+	return &podWatcher{
+		pm:              pm,
+		namespace:       namespace,
+		existingPods:    make(map[string]*corev1.Pod),
+		phaseSince:      make(map[string]time.Time),
+		lingerReported:  make(map[string]bool),
+		pendingReported: make(map[string]bool),
+		flaps:           make(map[string]*flapState),
+		lastModified:    make(map[string]lastModified),
+		startupReported: make(map[string]bool),
+		kubeEvents:      make(map[string]*KubeEvent),
+		probeFailures:   make(map[string]map[string]probeFailure),
+
+		terminatingReported: make(map[string]bool),
+		deletedPlacements:   make(map[string]deletedPlacement),
+	}
+}
+
+// label names the watched namespace in log lines.
+func (w *podWatcher) label() string {
+	if w.namespace == "" {
+		return namespaceLabel(nil)
+	}
+	return w.namespace
+}
+
+// trackedPod looks a pod up across all watchers.
+func (pm *PodMonitor) trackedPod(uid types.UID) (*corev1.Pod, bool) {
+	for _, w := range pm.podWatchers() {
+		if pod, exists := w.trackedPod(uid); exists {
+			return pod, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,205 @@
+package monitor
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// asyncSink is implemented by sinks that buffer events and deliver them from
+// their own goroutine, so Emit never blocks the watch loop.
+type asyncSink interface {
+	// Emit queues an event, returning an error when the buffer is full.
+	EventSink
+	// run delivers queued events until Close is called.
+	run()
+	// Close stops accepting events and waits up to timeout for delivery.
+	Close(timeout time.Duration)
+	// pending is the number of queued events not yet taken for delivery.
+	pending() int
+}
+
+// webhookSink POSTs each event as JSON to a URL. Requests that fail with a
+// network error or a 5xx response are retried with exponential backoff;
+// other failures are logged and the event is dropped.
+type webhookSink struct {
+	url        string
+	maxRetries int
+	client     *http.Client
+	logger     *log.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	events chan PodEvent
+	done   chan struct{}
+}
+
+// newWebhookTLSConfig builds the TLS settings shared by the webhook, Slack
+// and Teams clients. It returns nil, keeping Go's defaults, when neither a
+// CA file nor insecureSkipVerify is set.
+func newWebhookTLSConfig(caFile string, insecureSkipVerify bool, logger *log.Logger) (*tls.Config, error) {
+	caFile = strings.TrimSpace(caFile)
+	if caFile == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook CA file: %v", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("webhook CA file %s contains no PEM certificates", caFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	if insecureSkipVerify {
+		logger.Println("⚠️  WEBHOOK_INSECURE_SKIP_VERIFY is on: TLS certificates of the webhook, Slack and Teams endpoints are NOT verified. Never use this in production!")
+		tlsConfig.InsecureSkipVerify = true
+	}
+	return tlsConfig, nil
+}
+
+// newHTTPClient returns a client with timeout, using tlsConfig when it is
+// not nil.
+func newHTTPClient(timeout time.Duration, tlsConfig *tls.Config) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+	return client
+}
+
+// newWebhookSink builds a sink for url, reading WEBHOOK_TIMEOUT,
+// WEBHOOK_MAX_RETRIES and WEBHOOK_BUFFER_SIZE. tlsConfig may be nil. It
+// returns nil when url is empty.
+func newWebhookSink(url string, tlsConfig *tls.Config, logger *log.Logger) (*webhookSink, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("invalid webhook URL %q: must start with http:// or https://", url)
+	}
+
+	bufferSize := envInt("WEBHOOK_BUFFER_SIZE", 256)
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	maxRetries := envInt("WEBHOOK_MAX_RETRIES", 3)
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	return &webhookSink{
+		url:        url,
+		maxRetries: maxRetries,
+		client:     newHTTPClient(envDuration("WEBHOOK_TIMEOUT", 5*time.Second), tlsConfig),
+		logger:     logger,
+		events:     make(chan PodEvent, bufferSize),
+		done:       make(chan struct{}),
+	}, nil
+}
+
+// Emit queues an event for delivery without blocking.
+func (s *webhookSink) Emit(event PodEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil
+	}
+
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return errors.New("webhook buffer full")
+	}
+}
+
+// run delivers queued events one at a time until Close is called.
+func (s *webhookSink) run() {
+	defer close(s.done)
+
+	for event := range s.events {
+		s.deliver(event)
+	}
+}
+
+// Close stops accepting events and waits up to timeout for queued events to
+// be delivered.
+func (s *webhookSink) Close(timeout time.Duration) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.events)
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(timeout):
+		s.logger.Println("⚠️  Timed out delivering events to the webhook")
+	}
+}
+
+func (s *webhookSink) pending() int {
+	return len(s.events)
+}
+
+func (s *webhookSink) deliver(event PodEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Printf("❌ Failed to marshal event for the webhook: %v", err)
+		return
+	}
+
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body, event.contentType())
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= s.maxRetries {
+			s.logger.Printf("❌ Failed to deliver %s event for %s/%s to the webhook: %v",
+				event.EventType, event.Namespace, event.PodName, err)
+			return
+		}
+		time.Sleep(time.Duration(1<<attempt) * 500 * time.Millisecond)
+	}
+}
+
+// post sends one request and reports whether a failure is worth retrying.
+func (s *webhookSink) post(body []byte, contentType string) (bool, error) {
+	resp, err := s.client.Post(s.url, contentType, bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return resp.StatusCode >= 500, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(message)))
+}
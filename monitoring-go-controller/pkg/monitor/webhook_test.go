@@ -0,0 +1,228 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hookRequest is one request a hookServer received.
+type hookRequest struct {
+	contentType string
+	body        []byte
+}
+
+// hookServer is a webhook endpoint that answers with statuses in turn, then
+// with 200 OK, and records every request.
+type hookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []hookRequest
+}
+
+func newHookServer(t *testing.T, statuses ...int) *hookServer {
+	t.Helper()
+	s := &hookServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading webhook body: %v", err)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, hookRequest{contentType: r.Header.Get("Content-Type"), body: body})
+		if len(s.statuses) > 0 {
+			w.WriteHeader(s.statuses[0])
+			s.statuses = s.statuses[1:]
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *hookServer) received() []hookRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]hookRequest(nil), s.requests...)
+}
+
+// deliverAll runs sink over events and waits until they are delivered.
+func deliverAll(t *testing.T, sink asyncSink, events ...PodEvent) {
+	t.Helper()
+	go sink.run()
+	for _, event := range events {
+		if err := sink.Emit(event); err != nil {
+			t.Fatalf("Emit: %v", err)
+		}
+	}
+	sink.Close(10 * time.Second)
+}
+
+func TestWebhookSinkPostsEventJSON(t *testing.T) {
+	server := newHookServer(t)
+	sink, err := newWebhookSink(server.URL, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := PodEvent{
+		SchemaVersion: schemaVersion1,
+		Timestamp:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		EventType:     "DELETED",
+		PodName:       "web",
+		Namespace:     "shop",
+		Phase:         "Running",
+		Message:       "Pod deleted",
+	}
+	deliverAll(t, sink, event)
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("webhook received %d requests, want 1", len(requests))
+	}
+	if requests[0].contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", requests[0].contentType)
+	}
+	want, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(requests[0].body) != string(want) {
+		t.Errorf("body = %s, want %s", requests[0].body, want)
+	}
+}
+
+func TestWebhookSinkRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		retries  string
+		statuses []int
+		want     int
+	}{
+		{name: "5xx then success", retries: "3", statuses: []int{http.StatusBadGateway}, want: 2},
+		{name: "gives up after max retries", retries: "1", statuses: []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusInternalServerError}, want: 2},
+		{name: "4xx is not retried", retries: "3", statuses: []int{http.StatusBadRequest}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WEBHOOK_MAX_RETRIES", tt.retries)
+			server := newHookServer(t, tt.statuses...)
+			sink, err := newWebhookSink(server.URL, nil, log.New(io.Discard, "", 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			deliverAll(t, sink, PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop"})
+			requests := server.received()
+			if len(requests) != tt.want {
+				t.Fatalf("webhook received %d requests, want %d", len(requests), tt.want)
+			}
+			for _, r := range requests[1:] {
+				if string(r.body) != string(requests[0].body) {
+					t.Errorf("retry body = %s, want %s", r.body, requests[0].body)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookSinkTimesOutRequests(t *testing.T) {
+	t.Setenv("WEBHOOK_TIMEOUT", "50ms")
+	t.Setenv("WEBHOOK_MAX_RETRIES", "0")
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	sink, err := newWebhookSink(server.URL, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	deliverAll(t, sink, PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop"})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("delivery to a hung webhook took %v, want it cut off by the timeout", elapsed)
+	}
+}
+
+func TestWebhookSinkTLSSettings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		caFile     string
+		skipVerify bool
+		wantErr    bool
+	}{
+		{name: "system roots only", wantErr: true},
+		{name: "CA file", caFile: caFile},
+		{name: "insecure skip verify", skipVerify: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := log.New(&logs, "", 0)
+			tlsConfig, err := newWebhookTLSConfig(tt.caFile, tt.skipVerify, logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sink, err := newWebhookSink(server.URL, tlsConfig, logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = sink.post([]byte(`{}`), "application/json")
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("post error = %v, want error %v", err, tt.wantErr)
+			}
+			if warned := strings.Contains(logs.String(), "NOT verified"); warned != tt.skipVerify {
+				t.Errorf("skip-verify warning logged = %v, want %v", warned, tt.skipVerify)
+			}
+		})
+	}
+
+	if _, err := newWebhookTLSConfig(filepath.Join(t.TempDir(), "missing.pem"), false, log.New(io.Discard, "", 0)); err == nil {
+		t.Error("missing CA file accepted, want an error")
+	}
+}
+
+func TestWebhookSinkDropsWhenBufferFull(t *testing.T) {
+	t.Setenv("WEBHOOK_BUFFER_SIZE", "1")
+	sink, err := newWebhookSink("http://127.0.0.1:1", nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without run, nothing drains the buffer.
+	event := PodEvent{EventType: "ADDED", PodName: "web", Namespace: "shop"}
+	if err := sink.Emit(event); err != nil {
+		t.Fatalf("first Emit: %v", err)
+	}
+	if err := sink.Emit(event); err == nil {
+		t.Error("Emit into a full buffer succeeded, want an error")
+	}
+	if sink.pending() != 1 {
+		t.Errorf("pending = %d, want 1", sink.pending())
+	}
+}
@@ -0,0 +1,282 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// workloadKind adapts one workload controller type to workloadWatcher.
+type workloadKind struct {
+	// name is the kind as written in events and logs, e.g. "StatefulSet".
+	name      string
+	eventType string
+
+	list  func(ctx context.Context, namespace string) ([]metav1.Object, string, error)
+	watch func(ctx context.Context, namespace, resourceVersion string) (watch.Interface, error)
+	// changes describes the rollout status changes between two versions of
+	// an object, old being nil for a new object, and reports whether any of
+	// them needs attention. ok is false when obj is not of this kind.
+	changes func(old, obj runtime.Object) (reasons []string, warning, ok bool)
+}
+
+func statefulSetKind(clientset kubernetes.Interface) workloadKind {
+	return workloadKind{
+		name:      "StatefulSet",
+		eventType: "STATEFULSET_ROLLOUT",
+		list: func(ctx context.Context, namespace string) ([]metav1.Object, string, error) {
+			list, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, "", err
+			}
+			objects := make([]metav1.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, list.Items[i].DeepCopy())
+			}
+			return objects, list.ResourceVersion, nil
+		},
+		watch: func(ctx context.Context, namespace, resourceVersion string) (watch.Interface, error) {
+			return clientset.AppsV1().StatefulSets(namespace).Watch(ctx, metav1.ListOptions{
+				ResourceVersion:     resourceVersion,
+				AllowWatchBookmarks: true,
+			})
+		},
+		changes: func(old, obj runtime.Object) ([]string, bool, bool) {
+			set, ok := obj.(*appsv1.StatefulSet)
+			if !ok {
+				return nil, false, false
+			}
+			previous, ok := old.(*appsv1.StatefulSet)
+			if !ok {
+				// New object: nothing to compare with.
+				return nil, false, true
+			}
+			reasons, warning := statefulSetChanges(previous, set)
+			return reasons, warning, true
+		},
+	}
+}
+
+func daemonSetKind(clientset kubernetes.Interface) workloadKind {
+	return workloadKind{
+		name:      "DaemonSet",
+		eventType: "DAEMONSET_ROLLOUT",
+		list: func(ctx context.Context, namespace string) ([]metav1.Object, string, error) {
+			list, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, "", err
+			}
+			objects := make([]metav1.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, list.Items[i].DeepCopy())
+			}
+			return objects, list.ResourceVersion, nil
+		},
+		watch: func(ctx context.Context, namespace, resourceVersion string) (watch.Interface, error) {
+			return clientset.AppsV1().DaemonSets(namespace).Watch(ctx, metav1.ListOptions{
+				ResourceVersion:     resourceVersion,
+				AllowWatchBookmarks: true,
+			})
+		},
+		changes: func(old, obj runtime.Object) ([]string, bool, bool) {
+			set, ok := obj.(*appsv1.DaemonSet)
+			if !ok {
+				return nil, false, false
+			}
+			previous, ok := old.(*appsv1.DaemonSet)
+			if !ok {
+				// New object: nothing to compare with.
+				return nil, false, true
+			}
+			reasons, warning := daemonSetChanges(previous, set)
+			return reasons, warning, true
+		},
+	}
+}
+
+// statefulSetChanges reports revision and ready replica changes. Losing
+// ready replicas is a warning.
+func statefulSetChanges(old, set *appsv1.StatefulSet) ([]string, bool) {
+	var reasons []string
+	if old.Status.UpdateRevision != set.Status.UpdateRevision {
+		reasons = append(reasons, fmt.Sprintf("Update revision changed from %s to %s", old.Status.UpdateRevision, set.Status.UpdateRevision))
+	}
+	if old.Status.CurrentRevision != set.Status.CurrentRevision {
+		reasons = append(reasons, fmt.Sprintf("Current revision changed from %s to %s", old.Status.CurrentRevision, set.Status.CurrentRevision))
+	}
+	if old.Status.ReadyReplicas != set.Status.ReadyReplicas {
+		reasons = append(reasons, fmt.Sprintf("Ready replicas changed from %d to %d (of %d)",
+			old.Status.ReadyReplicas, set.Status.ReadyReplicas, set.Status.Replicas))
+	}
+	return reasons, set.Status.ReadyReplicas < old.Status.ReadyReplicas
+}
+
+// daemonSetChanges reports ready, updated and unavailable pod count changes.
+// More unavailable pods is a warning.
+func daemonSetChanges(old, set *appsv1.DaemonSet) ([]string, bool) {
+	var reasons []string
+	if old.Status.NumberReady != set.Status.NumberReady {
+		reasons = append(reasons, fmt.Sprintf("Ready pods changed from %d to %d (of %d)",
+			old.Status.NumberReady, set.Status.NumberReady, set.Status.DesiredNumberScheduled))
+	}
+	if old.Status.UpdatedNumberScheduled != set.Status.UpdatedNumberScheduled {
+		reasons = append(reasons, fmt.Sprintf("Updated pods changed from %d to %d (of %d)",
+			old.Status.UpdatedNumberScheduled, set.Status.UpdatedNumberScheduled, set.Status.DesiredNumberScheduled))
+	}
+	if old.Status.NumberUnavailable != set.Status.NumberUnavailable {
+		reasons = append(reasons, fmt.Sprintf("Unavailable pods changed from %d to %d",
+			old.Status.NumberUnavailable, set.Status.NumberUnavailable))
+	}
+	return reasons, set.Status.NumberUnavailable > old.Status.NumberUnavailable
+}
+
+// workloadWatcher watches one workload kind in one namespace, or in all
+// namespaces when namespace is empty, and emits an event of the kind's type
+// whenever an object's rollout status changes. Like the node watcher it
+// reconnects with the pod watchers' backoff and never gives up.
+type workloadWatcher struct {
+	pm        *PodMonitor
+	kind      workloadKind
+	namespace string
+
+	retryCount int
+
+	mu      sync.Mutex
+	objects map[string]runtime.Object
+}
+
+func newWorkloadWatcher(pm *PodMonitor, kind workloadKind, namespace string) *workloadWatcher {
+	return &workloadWatcher{
+		pm:        pm,
+		kind:      kind,
+		namespace: namespace,
+		objects:   make(map[string]runtime.Object),
+	}
+}
+
+func (w *workloadWatcher) run(ctx context.Context) {
+	pm := w.pm
+	kind := w.kind.name
+
+	r := &resourceWatch{
+		logger: pm.logger,
+		list:   w.list,
+		watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return w.kind.watch(ctx, w.namespace, resourceVersion)
+		},
+		handle:  w.handle,
+		backoff: w.backoff,
+		onEvent: func() { w.retryCount = 0 },
+
+		messages: watchMessages{
+			listFailed:  "❌ Failed to list " + kind + "s: %v",
+			watchFailed: "❌ Failed to create " + kind + " watcher: %v",
+			expired:     "⚠️  " + kind + " watch resource version expired, relisting",
+			watchError:  "❌ " + kind + " watch error: %v",
+			unexpected:  "⚠️  Unexpected object type in " + kind + " watch: %T",
+			cancelled:   "🛑 Context cancelled, stopping " + kind + " watcher",
+		},
+	}
+	r.run(ctx)
+}
+
+// list records the current objects without emitting events.
+func (w *workloadWatcher) list(ctx context.Context) (string, error) {
+	objects, resourceVersion, err := w.kind.list(ctx, w.namespace)
+	if err != nil {
+		return "", err
+	}
+
+	tracked := make(map[string]runtime.Object, len(objects))
+	for _, object := range objects {
+		if obj, ok := object.(runtime.Object); ok {
+			tracked[object.GetNamespace()+"/"+object.GetName()] = obj
+		}
+	}
+
+	w.mu.Lock()
+	w.objects = tracked
+	w.mu.Unlock()
+
+	w.pm.logger.Printf("🚢 Watching %ss in namespace %s (found %d)", w.kind.name, w.label(), len(tracked))
+	return resourceVersion, nil
+}
+
+func (w *workloadWatcher) handle(eventType watch.EventType, obj runtime.Object) bool {
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		return false
+	}
+	key := object.GetNamespace() + "/" + object.GetName()
+
+	w.mu.Lock()
+	old := w.objects[key]
+	w.mu.Unlock()
+	if eventType != watch.Modified {
+		old = nil
+	}
+	reasons, warning, ok := w.kind.changes(old, obj)
+	if !ok {
+		return false
+	}
+	if eventType == watch.Bookmark {
+		return true
+	}
+
+	w.mu.Lock()
+	if eventType == watch.Deleted {
+		delete(w.objects, key)
+	} else {
+		w.objects[key] = obj.DeepCopyObject()
+	}
+	w.mu.Unlock()
+
+	if len(reasons) == 0 || !w.pm.namespaceInScope(object.GetNamespace()) {
+		return true
+	}
+
+	event := PodEvent{
+		Timestamp: time.Now(),
+		EventType: w.kind.eventType,
+		Namespace: object.GetNamespace(),
+		Workload:  w.kind.name + "/" + object.GetName(),
+		Labels:    object.GetLabels(),
+		Message:   fmt.Sprintf("%s rollout status changed", w.kind.name),
+		Reason:    strings.Join(reasons, "; "),
+	}
+	if warning {
+		event.Severity = severityWarning
+	}
+	w.pm.logEvent(event)
+	return true
+}
+
+// backoff waits before the next reconnect attempt and never gives up.
+func (w *workloadWatcher) backoff(ctx context.Context) error {
+	w.retryCount++
+	backoffDuration := w.pm.backoff.delay(w.retryCount)
+	w.pm.logger.Printf("⚠️  %s watch interrupted, retrying in %v (attempt %d)", w.kind.name, backoffDuration, w.retryCount)
+
+	select {
+	case <-time.After(backoffDuration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// label names the watched namespace in log lines.
+func (w *workloadWatcher) label() string {
+	if w.namespace == "" {
+		return namespaceLabel(nil)
+	}
+	return w.namespace
+}